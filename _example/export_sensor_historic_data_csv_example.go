@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+func main() {
+	// Configuration
+	server := "https://prtg.paessler.com"
+	username := "demo"
+	password := "demodemo"
+	client := prtg.NewClient(server, username, password)
+
+	var sensorId int64 = 7986 // Sensor Homepage
+	startDate := time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2018, time.May, 1, 0, 35, 0, 0, time.UTC)
+	var average int64 = 0
+	histData, err := client.GetHistoricData(sensorId, average, startDate, endDate)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// Write the raw values with RFC3339 datetime
+	opts := &prtg.HistoricDataCSVOptions{
+		Raw:        true,
+		TimeFormat: time.RFC3339,
+		Location:   time.Local,
+	}
+	err = prtg.WriteHistoricDataCSV(os.Stdout, histData, opts)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// Or use PRTG's CSV as is
+	err = client.GetHistoricDataCSV(os.Stdout, sensorId, average, startDate, endDate)
+	if err != nil {
+		log.Println(err)
+	}
+}
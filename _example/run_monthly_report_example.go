@@ -0,0 +1,50 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+func main() {
+	// Configuration
+	server := "https://prtg.paessler.com"
+	username := "demo"
+	password := "demodemo"
+	client := prtg.NewClient(server, username, password)
+
+	reports, err := client.GetReportList(nil)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	for _, report := range reports {
+		log.Printf("%v: %v (%v)", report.ObjectId, report.Name, report.Period)
+	}
+
+	// Run the first report for the previous month
+	now := time.Now()
+	endDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	startDate := endDate.AddDate(0, -1, 0)
+	reportId := reports[0].ObjectId
+	err = client.RunReport(reportId, startDate, endDate)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// PRTG generates the report in background
+	time.Sleep(time.Minute)
+	file, err := os.Create("report.pdf")
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer file.Close()
+	_, err = client.DownloadReport(file, reportId, prtg.ReportPDF)
+	if err != nil {
+		log.Println(err)
+	}
+}
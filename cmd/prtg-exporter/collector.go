@@ -0,0 +1,273 @@
+package main
+
+import (
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+// prtgClient contains the client's methods used by the collector.
+type prtgClient interface {
+	GetSensorTree(id int64) (*prtg.PrtgSensorTreeResponse, error)
+	GetChannelList(id int64, columns []string) ([]prtg.PrtgChannelList, error)
+}
+
+// filter selects the exported sensors.
+type filter struct {
+	// Sensors should have at least one of these tags, including the inherited ones
+	includeTags []string
+	// Sensors having any of these tags are skipped
+	excludeTags []string
+	// Sensor's type, matched against both sensortype and sensorkind
+	sensorTypes []string
+	// Sensor's name pattern
+	sensorName *regexp.Regexp
+	// Skip the sensors which are not active
+	skipPaused bool
+}
+
+func (f *filter) match(sensor *prtg.SensorTreeSensor, tags []string) bool {
+	if f.skipPaused && !sensor.SensorActive {
+		return false
+	}
+	if len(f.includeTags) > 0 && !containsAny(tags, f.includeTags) {
+		return false
+	}
+	if containsAny(tags, f.excludeTags) {
+		return false
+	}
+	if len(f.sensorTypes) > 0 && !containsAny([]string{sensor.SensorType, sensor.SensorKind}, f.sensorTypes) {
+		return false
+	}
+	if f.sensorName != nil && !f.sensorName.MatchString(sensor.SensorName) {
+		return false
+	}
+	return true
+}
+
+func containsAny(values, candidates []string) bool {
+	for _, value := range values {
+		for _, candidate := range candidates {
+			if strings.EqualFold(value, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sensorLabels contains the location of the sensor within PRTG's tree.
+type sensorLabels struct {
+	probe      string
+	group      string
+	device     string
+	sensor     string
+	sensorID   int64
+	sensorType string
+	tags       []string
+}
+
+type channelSample struct {
+	id    int64
+	name  string
+	value float64
+}
+
+type sensorSample struct {
+	labels    sensorLabels
+	status    string
+	statusRaw int64
+	lastValue float64
+	uptime    float64
+	downtime  float64
+	channels  []channelSample
+}
+
+// snapshot contains the result of the latest refresh.
+type snapshot struct {
+	sensors   []sensorSample
+	up        bool
+	duration  time.Duration
+	timestamp time.Time
+}
+
+// collector refreshes the sensors in background, so scrapes are served from memory.
+type collector struct {
+	client             prtgClient
+	rootID             int64
+	filter             filter
+	channels           bool
+	channelConcurrency int
+
+	mu   sync.RWMutex
+	last snapshot
+}
+
+func (c *collector) snapshot() snapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.last
+}
+
+// refresh reads the sensor tree, and the channels if enabled.
+// The previous sensors are kept if PRTG is unreachable.
+func (c *collector) refresh() {
+	start := time.Now()
+	sensors, err := c.collect()
+	next := c.snapshot()
+	next.up = err == nil
+	next.duration = time.Since(start)
+	next.timestamp = start
+	if err != nil {
+		log.Printf("Unable to refresh PRTG's sensors: %v", err)
+	} else {
+		next.sensors = sensors
+	}
+	c.mu.Lock()
+	c.last = next
+	c.mu.Unlock()
+}
+
+// run refreshes every interval until stop is closed.
+func (c *collector) run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.refresh()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *collector) collect() ([]sensorSample, error) {
+	tree, err := c.client.GetSensorTree(c.rootID)
+	if err != nil {
+		return nil, err
+	}
+	w := treeWalker{filter: &c.filter}
+	root := sensorLabels{}
+	w.groups(tree.Groups, root)
+	w.probeNodes(tree.ProbeNodes, root)
+	w.devices(tree.Devices, root)
+	w.sensors(tree.Sensors, root)
+	if c.channels {
+		c.collectChannels(w.samples)
+	}
+	return w.samples, nil
+}
+
+// collectChannels fills the channels of each sensor.
+// The sensor is exported without channels if its channels are unavailable.
+func (c *collector) collectChannels(samples []sensorSample) {
+	concurrency := c.channelConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range samples {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(sample *sensorSample) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			channelList, err := c.client.GetChannelList(sample.labels.sensorID, nil)
+			if err != nil {
+				log.Printf("Unable to get channels of sensor %v: %v", sample.labels.sensorID, err)
+				return
+			}
+			for _, channel := range channelList {
+				value, ok := channel.RawValue()
+				if !ok {
+					continue
+				}
+				sample.channels = append(sample.channels, channelSample{
+					id:    channel.ObjectId,
+					name:  channel.Name,
+					value: value,
+				})
+			}
+		}(&samples[i])
+	}
+	wg.Wait()
+}
+
+// treeWalker flattens the sensor tree, passing the labels and inherited tags down.
+type treeWalker struct {
+	filter  *filter
+	samples []sensorSample
+}
+
+func (w *treeWalker) groups(groups []prtg.SensorTreeGroup, labels sensorLabels) {
+	for _, group := range groups {
+		l := labels
+		l.group = group.GroupName
+		l.tags = appendTags(labels.tags, group.GroupTags)
+		w.groups(group.Groups, l)
+		w.probeNodes(group.ProbeNodes, l)
+		w.devices(group.Devices, l)
+		w.sensors(group.Sensors, l)
+	}
+}
+
+func (w *treeWalker) probeNodes(probeNodes []prtg.SensorTreeProbeNode, labels sensorLabels) {
+	for _, probeNode := range probeNodes {
+		l := labels
+		// The probe is the parent group of its devices, as shown by PRTG
+		l.probe = probeNode.ProbeName
+		l.group = probeNode.ProbeName
+		w.groups(probeNode.Groups, l)
+		w.devices(probeNode.Devices, l)
+		w.sensors(probeNode.Sensors, l)
+	}
+}
+
+func (w *treeWalker) devices(devices []prtg.SensorTreeDevice, labels sensorLabels) {
+	for _, device := range devices {
+		l := labels
+		l.device = device.DeviceName
+		l.tags = appendTags(labels.tags, device.DeviceTags)
+		w.sensors(device.Sensors, l)
+	}
+}
+
+func (w *treeWalker) sensors(sensors []prtg.SensorTreeSensor, labels sensorLabels) {
+	for i := range sensors {
+		sensor := &sensors[i]
+		l := labels
+		l.sensor = sensor.SensorName
+		l.sensorID = sensor.SensorId
+		l.sensorType = sensor.SensorType
+		l.tags = appendTags(labels.tags, sensor.SensorTags)
+		if !w.filter.match(sensor, l.tags) {
+			continue
+		}
+		w.samples = append(w.samples, sensorSample{
+			labels:    l,
+			status:    sensor.SensorStatus,
+			statusRaw: sensor.SensorStatusRaw,
+			lastValue: sensor.SensorLastValue,
+			uptime:    sensor.SensorCumulatedUpTime,
+			downtime:  sensor.SensorCumulatedDownTime,
+		})
+	}
+}
+
+// appendTags returns a new slice of the inherited tags followed by PRTG's space separated tags, without duplicates.
+func appendTags(inherited []string, tags string) []string {
+	result := make([]string, len(inherited), len(inherited)+4)
+	copy(result, inherited)
+	for _, tag := range strings.Fields(tags) {
+		if !containsAny(result, []string{tag}) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
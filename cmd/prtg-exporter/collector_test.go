@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+type fakeClient struct {
+	tree     *prtg.PrtgSensorTreeResponse
+	err      error
+	channels map[int64][]prtg.PrtgChannelList
+}
+
+func (f *fakeClient) GetSensorTree(id int64) (*prtg.PrtgSensorTreeResponse, error) {
+	return f.tree, f.err
+}
+
+func (f *fakeClient) GetChannelList(id int64, columns []string) ([]prtg.PrtgChannelList, error) {
+	channels, ok := f.channels[id]
+	if !ok {
+		return nil, fmt.Errorf("No Data Found")
+	}
+	return channels, nil
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		tree: &prtg.PrtgSensorTreeResponse{
+			Groups: []prtg.SensorTreeGroup{{
+				GroupName: "Root",
+				ProbeNodes: []prtg.SensorTreeProbeNode{{
+					ProbeName: "Local Probe",
+					Groups: []prtg.SensorTreeGroup{{
+						GroupName: "Network",
+						GroupTags: "network",
+						Devices: []prtg.SensorTreeDevice{{
+							DeviceName: "Core \"Router\"",
+							DeviceTags: "cisco",
+							Sensors: []prtg.SensorTreeSensor{
+								{
+									SensorId:                1001,
+									SensorName:              "Ping",
+									SensorType:              "Ping",
+									SensorKind:              "ping",
+									SensorStatus:            "Up",
+									SensorStatusRaw:         3,
+									SensorLastValue:         12,
+									SensorCumulatedUpTime:   3600,
+									SensorCumulatedDownTime: 60,
+									SensorActive:            true,
+								},
+								{
+									SensorId:        1002,
+									SensorName:      "Traffic Gi0/1",
+									SensorTags:      "bandwidthsensor",
+									SensorType:      "SNMP Traffic",
+									SensorKind:      "snmptraffic",
+									SensorStatus:    "Paused",
+									SensorStatusRaw: 7,
+									SensorActive:    false,
+								},
+							},
+						}},
+					}},
+					Devices: []prtg.SensorTreeDevice{{
+						DeviceName: "Probe Device",
+						Sensors: []prtg.SensorTreeSensor{{
+							SensorId:        1003,
+							SensorName:      "Core Health",
+							SensorStatus:    "Down",
+							SensorStatusRaw: 5,
+							SensorActive:    true,
+						}},
+					}},
+				}},
+			}},
+		},
+		channels: map[int64][]prtg.PrtgChannelList{
+			1001: {
+				{ObjectId: 0, Name: "Ping Time", LastValueRaw: float64(12)},
+				{ObjectId: -4, Name: "Downtime", LastValueRaw: ""},
+			},
+		},
+	}
+}
+
+func TestCollectorRefresh(t *testing.T) {
+	client := newFakeClient()
+	c := &collector{client: client, channels: true, channelConcurrency: 2}
+	c.refresh()
+
+	s := c.snapshot()
+	if !s.up {
+		t.Errorf("The refresh should be success")
+	}
+	if len(s.sensors) != 3 {
+		t.Errorf("There should be 3 sensors instead of %v", len(s.sensors))
+		return
+	}
+	ping := s.sensors[0].labels
+	if ping.probe != "Local Probe" || ping.group != "Network" || ping.device != "Core \"Router\"" {
+		t.Errorf("Unexpected labels of the sensor: %+v", ping)
+	}
+	if strings.Join(ping.tags, ",") != "network,cisco" {
+		t.Errorf("Sensor's tags are %v instead of network,cisco", ping.tags)
+	}
+	if probeDevice := s.sensors[2].labels; probeDevice.group != "Local Probe" {
+		t.Errorf("The group of device within probe is %v instead of Local Probe", probeDevice.group)
+	}
+	if len(s.sensors[0].channels) != 1 || len(s.sensors[1].channels) != 0 {
+		t.Errorf("Only the channel having value should be exported")
+	}
+
+	// The previous sensors are kept if PRTG is unreachable
+	client.err = fmt.Errorf("Unable to create HTTP request")
+	c.refresh()
+	s = c.snapshot()
+	if s.up || len(s.sensors) != 3 {
+		t.Errorf("It should be down but keep the %v sensors, instead of %v", 3, len(s.sensors))
+	}
+}
+
+func TestCollectorFilter(t *testing.T) {
+	testCases := []struct {
+		filter   filter
+		expected []int64
+	}{
+		{filter{}, []int64{1001, 1002, 1003}},
+		{filter{includeTags: []string{"cisco"}}, []int64{1001, 1002}},
+		{filter{excludeTags: []string{"BandwidthSensor"}}, []int64{1001, 1003}},
+		{filter{sensorTypes: []string{"ping"}}, []int64{1001}},
+		{filter{sensorName: regexp.MustCompile("^Core")}, []int64{1003}},
+		{filter{skipPaused: true}, []int64{1001, 1003}},
+	}
+	for _, tc := range testCases {
+		c := &collector{client: newFakeClient(), filter: tc.filter}
+		sensors, err := c.collect()
+		if err != nil {
+			t.Errorf("It should be success but error: %v", err)
+			continue
+		}
+		var ids []int64
+		for _, sensor := range sensors {
+			ids = append(ids, sensor.labels.sensorID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tc.expected) {
+			t.Errorf("Filter %+v exports %v instead of %v", tc.filter, ids, tc.expected)
+		}
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	c := &collector{client: newFakeClient(), channels: true}
+	c.refresh()
+
+	var buf bytes.Buffer
+	if err := writeMetrics(&buf, c.snapshot()); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	metrics := buf.String()
+	expected := []string{
+		"# TYPE prtg_up gauge\nprtg_up 1\n",
+		`prtg_sensor_status{probe="Local Probe",group="Network",device="Core \"Router\"",sensor="Ping",sensor_id="1001",sensor_type="Ping",tags="network,cisco",status="Up"} 3`,
+		"# TYPE prtg_sensor_uptime_seconds_total counter\n",
+		`prtg_sensor_downtime_seconds_total{probe="Local Probe",group="Network",device="Core \"Router\"",sensor="Ping",sensor_id="1001",sensor_type="Ping",tags="network,cisco"} 60`,
+		`prtg_channel_last_value{probe="Local Probe",group="Network",device="Core \"Router\"",sensor="Ping",sensor_id="1001",sensor_type="Ping",tags="network,cisco",channel="Ping Time",channel_id="0"} 12`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(metrics, line) {
+			t.Errorf("Metrics should contain %q:\n%v", line, metrics)
+		}
+	}
+
+	// Nothing but the refresh's metrics before the first refresh
+	buf.Reset()
+	if err := writeMetrics(&buf, snapshot{}); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if strings.Contains(buf.String(), "sensor") {
+		t.Errorf("Metrics should not contain any sensor:\n%v", buf.String())
+	}
+}
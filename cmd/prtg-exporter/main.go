@@ -0,0 +1,105 @@
+// Command prtg-exporter exposes PRTG's sensors as Prometheus metrics.
+//
+// The sensors are refreshed from PRTG in background,
+// so scrapes are served from memory and don't reach PRTG.
+//
+// Usage:
+//
+//	PRTG_PASSWORD=secret prtg-exporter -prtg.server https://prtg.example.com -prtg.username monitor
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+func main() {
+	var (
+		listenAddress      = flag.String("web.listen-address", ":9705", "Address to listen on for scrapes.")
+		metricsPath        = flag.String("web.telemetry-path", "/metrics", "Path under which to expose metrics.")
+		server             = flag.String("prtg.server", "", "PRTG's server URL, e.g. https://prtg.example.com.")
+		username           = flag.String("prtg.username", "", "PRTG's username.")
+		password           = flag.String("prtg.password", os.Getenv("PRTG_PASSWORD"), "PRTG's password, defaults to $PRTG_PASSWORD.")
+		passwordHash       = flag.String("prtg.passhash", os.Getenv("PRTG_PASSHASH"), "PRTG's password hash, defaults to $PRTG_PASSHASH.")
+		timeout            = flag.Duration("prtg.timeout", 10*time.Second, "Timeout of each request to PRTG.")
+		rootID             = flag.Int64("prtg.root-id", 0, "Object id of the group, probe, or device to export.")
+		refreshInterval    = flag.Duration("refresh.interval", time.Minute, "Interval between refreshes from PRTG.")
+		channels           = flag.Bool("refresh.channels", false, "Export the last value of each channel, one request per sensor.")
+		channelConcurrency = flag.Int("refresh.channel-concurrency", 4, "Maximum concurrent channel requests.")
+		includeTags        = flag.String("filter.include-tags", "", "Comma separated tags, export only the sensors having any of them.")
+		excludeTags        = flag.String("filter.exclude-tags", "", "Comma separated tags, skip the sensors having any of them.")
+		sensorTypes        = flag.String("filter.sensor-types", "", "Comma separated sensor's types or kinds to export.")
+		sensorName         = flag.String("filter.sensor-name", "", "Regular expression of the sensor's names to export.")
+		skipPaused         = flag.Bool("filter.skip-paused", false, "Skip the sensors which are not active.")
+	)
+	flag.Parse()
+
+	if *server == "" || *username == "" {
+		log.Fatal("prtg.server and prtg.username are mandatory")
+	}
+	if *password == "" && *passwordHash == "" {
+		log.Fatal("Either prtg.password or prtg.passhash is mandatory")
+	}
+	if *refreshInterval <= 0 {
+		log.Fatal("refresh.interval should be more than zero")
+	}
+	var client *prtg.Client
+	if *password != "" {
+		client = prtg.NewClient(*server, *username, *password)
+	} else {
+		client = prtg.NewClientWithHashedPass(*server, *username, *passwordHash)
+	}
+	client.SetContextTimeout(timeout.Milliseconds())
+
+	c := &collector{
+		client:             client,
+		rootID:             *rootID,
+		channels:           *channels,
+		channelConcurrency: *channelConcurrency,
+		filter: filter{
+			includeTags: splitList(*includeTags),
+			excludeTags: splitList(*excludeTags),
+			sensorTypes: splitList(*sensorTypes),
+			skipPaused:  *skipPaused,
+		},
+	}
+	if *sensorName != "" {
+		pattern, err := regexp.Compile(*sensorName)
+		if err != nil {
+			log.Fatalf("Invalid filter.sensor-name: %v", err)
+		}
+		c.filter.sensorName = pattern
+	}
+	go c.run(*refreshInterval, nil)
+
+	http.Handle(*metricsPath, c)
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "<html><head><title>PRTG Exporter</title></head><body><h1>PRTG Exporter</h1>"+
+			"<p><a href=\"%v\">Metrics</a></p></body></html>", *metricsPath)
+	})
+	log.Printf("Listening on %v", *listenAddress)
+	log.Fatal(http.ListenAndServe(*listenAddress, nil))
+}
+
+// splitList splits comma separated values, ignoring the empty ones.
+func splitList(str string) []string {
+	var list []string
+	for _, value := range strings.Split(str, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
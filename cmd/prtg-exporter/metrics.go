@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	namespace = "prtg"
+	// contentType of Prometheus text exposition format
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+// metricWriter writes Prometheus text exposition format.
+type metricWriter struct {
+	w *bufio.Writer
+}
+
+func (mw *metricWriter) family(name, help, metricType string) {
+	fmt.Fprintf(mw.w, "# HELP %v_%v %v\n", namespace, name, help)
+	fmt.Fprintf(mw.w, "# TYPE %v_%v %v\n", namespace, name, metricType)
+}
+
+func (mw *metricWriter) sample(name string, labels []label, value float64) {
+	fmt.Fprintf(mw.w, "%v_%v", namespace, name)
+	if len(labels) > 0 {
+		mw.w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				mw.w.WriteByte(',')
+			}
+			fmt.Fprintf(mw.w, "%v=\"%v\"", l.name, escapeLabelValue(l.value))
+		}
+		mw.w.WriteByte('}')
+	}
+	mw.w.WriteByte(' ')
+	mw.w.WriteString(formatValue(value))
+	mw.w.WriteByte('\n')
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func (l *sensorLabels) labels() []label {
+	return []label{
+		{"probe", l.probe},
+		{"group", l.group},
+		{"device", l.device},
+		{"sensor", l.sensor},
+		{"sensor_id", strconv.FormatInt(l.sensorID, 10)},
+		{"sensor_type", l.sensorType},
+		{"tags", strings.Join(l.tags, ",")},
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// writeMetrics writes the snapshot's metrics into w.
+func writeMetrics(w io.Writer, s snapshot) error {
+	mw := metricWriter{w: bufio.NewWriter(w)}
+
+	mw.family("up", "Whether the last refresh from PRTG was successful.", "gauge")
+	mw.sample("up", nil, boolValue(s.up))
+	mw.family("refresh_duration_seconds", "Duration of the last refresh from PRTG.", "gauge")
+	mw.sample("refresh_duration_seconds", nil, s.duration.Seconds())
+	if !s.timestamp.IsZero() {
+		mw.family("last_refresh_timestamp_seconds", "Unix time of the last refresh from PRTG.", "gauge")
+		mw.sample("last_refresh_timestamp_seconds", nil, float64(s.timestamp.UnixNano())/1e9)
+	}
+	if len(s.sensors) == 0 {
+		return mw.w.Flush()
+	}
+
+	mw.family("sensor_status", "PRTG's raw status of the sensor, e.g. 3 for Up and 5 for Down.", "gauge")
+	for i := range s.sensors {
+		sensor := &s.sensors[i]
+		mw.sample("sensor_status", append(sensor.labels.labels(), label{"status", sensor.status}), float64(sensor.statusRaw))
+	}
+	mw.family("sensor_last_value", "Last raw value of the sensor's primary channel.", "gauge")
+	for i := range s.sensors {
+		sensor := &s.sensors[i]
+		mw.sample("sensor_last_value", sensor.labels.labels(), sensor.lastValue)
+	}
+	mw.family("sensor_uptime_seconds_total", "Cumulated uptime of the sensor.", "counter")
+	for i := range s.sensors {
+		sensor := &s.sensors[i]
+		mw.sample("sensor_uptime_seconds_total", sensor.labels.labels(), sensor.uptime)
+	}
+	mw.family("sensor_downtime_seconds_total", "Cumulated downtime of the sensor.", "counter")
+	for i := range s.sensors {
+		sensor := &s.sensors[i]
+		mw.sample("sensor_downtime_seconds_total", sensor.labels.labels(), sensor.downtime)
+	}
+
+	familyWritten := false
+	for i := range s.sensors {
+		sensor := &s.sensors[i]
+		for _, channel := range sensor.channels {
+			if !familyWritten {
+				mw.family("channel_last_value", "Last raw value of the sensor's channel.", "gauge")
+				familyWritten = true
+			}
+			labels := append(sensor.labels.labels(),
+				label{"channel", channel.name},
+				label{"channel_id", strconv.FormatInt(channel.id, 10)})
+			mw.sample("channel_last_value", labels, channel.value)
+		}
+	}
+	return mw.w.Flush()
+}
+
+// ServeHTTP serves the latest snapshot without requesting PRTG.
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	if err := writeMetrics(w, c.snapshot()); err != nil {
+		log.Printf("Unable to write metrics: %v", err)
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+func runVersion(e *env, client *prtg.Client, output string, args []string) (int, error) {
+	if len(args) > 0 {
+		return exitUsage, newUsageError("Too many arguments: %v", strings.Join(args, " "))
+	}
+	version, err := client.GetPrtgVersion()
+	if err != nil {
+		return exitUnknown, err
+	}
+	r := &result{
+		columns: []string{"version"},
+		rows:    [][]string{{version}},
+		value:   map[string]string{"version": version},
+	}
+	return exitOK, writeResult(e.stdout, output, r)
+}
+
+// getTableList calls the list API, tolerating its "No Data Found" error on empty list.
+func getTableList(list func(int64, []string) ([]prtg.PrtgTableList, error), args []string) ([]prtg.PrtgTableList, error) {
+	id, err := parseOptionalID(args)
+	if err != nil {
+		return nil, err
+	}
+	items, err := list(id, nil)
+	if err != nil && !(items != nil && len(items) == 0) {
+		return nil, err
+	}
+	if items == nil {
+		items = []prtg.PrtgTableList{}
+	}
+	return items, nil
+}
+
+func runSensors(e *env, client *prtg.Client, output string, args []string) (int, error) {
+	sensors, err := getTableList(client.GetSensorList, args)
+	if err != nil {
+		return exitCode(err), err
+	}
+	state := exitOK
+	r := &result{
+		columns: []string{"id", "probe", "group", "device", "sensor", "status", "lastvalue", "message"},
+		value:   sensors,
+	}
+	for _, s := range sensors {
+		state = worseState(state, sensorState(s.StatusRaw))
+		r.rows = append(r.rows, []string{fmt.Sprint(s.ObjectId), s.Probe, s.Group, s.Device, s.Sensor,
+			s.Status, s.LastValue, s.Message})
+	}
+	if err := writeResult(e.stdout, output, r); err != nil {
+		return exitUnknown, err
+	}
+	return state, nil
+}
+
+func runDevices(e *env, client *prtg.Client, output string, args []string) (int, error) {
+	devices, err := getTableList(client.GetDeviceList, args)
+	if err != nil {
+		return exitCode(err), err
+	}
+	r := &result{
+		columns: []string{"id", "probe", "group", "device", "host", "up", "warning", "down", "paused", "unknown"},
+		value:   devices,
+	}
+	for _, d := range devices {
+		r.rows = append(r.rows, []string{fmt.Sprint(d.ObjectId), d.Probe, d.Group, d.Device, d.Host,
+			fmt.Sprint(d.UpSensors), fmt.Sprint(d.WarningSensors), fmt.Sprint(d.DownSensors),
+			fmt.Sprint(d.PausedSensors), fmt.Sprint(d.UndefinedSensors)})
+	}
+	return exitOK, writeResult(e.stdout, output, r)
+}
+
+func runGroups(e *env, client *prtg.Client, output string, args []string) (int, error) {
+	groups, err := getTableList(client.GetGroupList, args)
+	if err != nil {
+		return exitCode(err), err
+	}
+	r := &result{
+		columns: []string{"id", "probe", "group", "name", "up", "warning", "down", "paused", "unknown"},
+		value:   groups,
+	}
+	for _, g := range groups {
+		r.rows = append(r.rows, []string{fmt.Sprint(g.ObjectId), g.Probe, g.Group, g.Name,
+			fmt.Sprint(g.UpSensors), fmt.Sprint(g.WarningSensors), fmt.Sprint(g.DownSensors),
+			fmt.Sprint(g.PausedSensors), fmt.Sprint(g.UndefinedSensors)})
+	}
+	return exitOK, writeResult(e.stdout, output, r)
+}
+
+func runTree(e *env, client *prtg.Client, output string, args []string) (int, error) {
+	id, err := parseOptionalID(args)
+	if err != nil {
+		return exitCode(err), err
+	}
+	tree, err := client.GetSensorTree(id)
+	if err != nil {
+		return exitUnknown, err
+	}
+	r := &result{
+		columns: []string{"type", "id", "name", "path", "status"},
+		value:   tree,
+	}
+	w := treeRows{r: r}
+	w.groups(tree.Groups, nil)
+	w.probeNodes(tree.ProbeNodes, nil)
+	w.devices(tree.Devices, nil)
+	w.sensors(tree.Sensors, nil)
+	return exitOK, writeResult(e.stdout, output, r)
+}
+
+// treeRows flattens the sensor tree into rows, in depth-first order.
+type treeRows struct {
+	r *result
+}
+
+func (t *treeRows) add(objectType string, id int64, name string, path []string, status string) {
+	t.r.rows = append(t.r.rows, []string{objectType, fmt.Sprint(id), name, strings.Join(path, " / "), status})
+}
+
+func (t *treeRows) groups(groups []prtg.SensorTreeGroup, path []string) {
+	for _, g := range groups {
+		t.add("group", g.GroupId, g.GroupName, path, "")
+		p := append(path[:len(path):len(path)], g.GroupName)
+		t.groups(g.Groups, p)
+		t.probeNodes(g.ProbeNodes, p)
+		t.devices(g.Devices, p)
+		t.sensors(g.Sensors, p)
+	}
+}
+
+func (t *treeRows) probeNodes(probeNodes []prtg.SensorTreeProbeNode, path []string) {
+	for _, n := range probeNodes {
+		t.add("probe", n.ProbeId, n.ProbeName, path, "")
+		p := append(path[:len(path):len(path)], n.ProbeName)
+		t.groups(n.Groups, p)
+		t.devices(n.Devices, p)
+		t.sensors(n.Sensors, p)
+	}
+}
+
+func (t *treeRows) devices(devices []prtg.SensorTreeDevice, path []string) {
+	for _, d := range devices {
+		t.add("device", d.DeviceId, d.DeviceName, path, "")
+		t.sensors(d.Sensors, append(path[:len(path):len(path)], d.DeviceName))
+	}
+}
+
+func (t *treeRows) sensors(sensors []prtg.SensorTreeSensor, path []string) {
+	for _, s := range sensors {
+		t.add("sensor", s.SensorId, s.SensorName, path, s.SensorStatus)
+	}
+}
+
+func runDetail(e *env, client *prtg.Client, output string, args []string) (int, error) {
+	ids, err := parseIDs(args)
+	if err != nil {
+		return exitCode(err), err
+	}
+	if len(ids) != 1 {
+		return exitUsage, newUsageError("Usage: prtgctl %v", commands["detail"].usage)
+	}
+	detail, err := client.GetSensorDetailTyped(ids[0])
+	if err != nil {
+		return exitUnknown, err
+	}
+	r := &result{
+		columns: []string{"id", "name", "type", "device", "group", "probe", "status", "lastvalue", "message", "lastcheck"},
+		rows: [][]string{{fmt.Sprint(ids[0]), detail.Name, detail.SensorType, detail.ParentDeviceName,
+			detail.ParentGroupName, detail.ProbeName, detail.StatusText, detail.LastValue, detail.LastMessage,
+			formatTime(detail.LastCheck)}},
+		value: detail,
+	}
+	if err := writeResult(e.stdout, output, r); err != nil {
+		return exitUnknown, err
+	}
+	return sensorState(detail.StatusID), nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// parseTime accepts RFC3339 or date only, in local time.
+func parseTime(str string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, str); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", str, time.Local)
+}
+
+func runHistory(e *env, client *prtg.Client, output string, args []string) (int, error) {
+	flags := newFlagSet(e, "history")
+	since := flags.Duration("since", 24*time.Hour, "Period until now, used if start is not set.")
+	start := flags.String("start", "", "Start of the period, in RFC3339 or 2006-01-02.")
+	end := flags.String("end", "", "End of the period, in RFC3339 or 2006-01-02, defaults to now.")
+	average := flags.Int64("avg", 0, "Average interval in seconds, 0 for raw data.")
+	raw := flags.Bool("raw", false, "Print the raw values instead of the formatted ones.")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage, nil
+	}
+	ids, err := parseIDs(flags.Args())
+	if err != nil {
+		return exitCode(err), err
+	}
+	if len(ids) != 1 {
+		flags.Usage()
+		return exitUsage, nil
+	}
+	endDate := time.Now()
+	if *end != "" {
+		if endDate, err = parseTime(*end); err != nil {
+			return exitUsage, newUsageError("Invalid end: %v", err)
+		}
+	}
+	startDate := endDate.Add(-*since)
+	if *start != "" {
+		if startDate, err = parseTime(*start); err != nil {
+			return exitUsage, newUsageError("Invalid start: %v", err)
+		}
+	}
+
+	histData, err := client.GetHistoricData(ids[0], *average, startDate, endDate)
+	if err != nil {
+		return exitUnknown, err
+	}
+	if output == outputJSON {
+		return exitOK, writeResult(e.stdout, output, &result{value: histData})
+	}
+	opts := &prtg.HistoricDataCSVOptions{Raw: *raw}
+	if output == outputCSV {
+		return exitOK, prtg.WriteHistoricDataCSV(e.stdout, histData, opts)
+	}
+	// Reuse the CSV's columns for the table
+	var buf bytes.Buffer
+	if err := prtg.WriteHistoricDataCSV(&buf, histData, opts); err != nil {
+		return exitUnknown, err
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		return exitUnknown, err
+	}
+	return exitOK, writeResult(e.stdout, output, &result{columns: records[0], rows: records[1:]})
+}
+
+func runPause(e *env, client *prtg.Client, output string, args []string) (int, error) {
+	flags := newFlagSet(e, "pause")
+	message := flags.String("message", "", "Message shown as the object's status.")
+	duration := flags.Duration("duration", 0, "Resume automatically after the duration, rounded up to minute.")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage, nil
+	}
+	return runAction(e, flags.Args(), "pause", func(id int64) error {
+		if *duration > 0 {
+			return client.PauseObjectFor(id, *message, *duration)
+		}
+		return client.PauseObject(id, *message)
+	})
+}
+
+func runResume(e *env, client *prtg.Client, output string, args []string) (int, error) {
+	return runAction(e, args, "resume", client.ResumeObject)
+}
+
+func runAck(e *env, client *prtg.Client, output string, args []string) (int, error) {
+	flags := newFlagSet(e, "ack")
+	message := flags.String("message", "", "Acknowledgement message.")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage, nil
+	}
+	return runAction(e, flags.Args(), "ack", func(id int64) error {
+		return client.AcknowledgeAlarm(id, *message)
+	})
+}
+
+// runAction applies the action to every id, and reports the failed ones.
+func runAction(e *env, args []string, name string, action func(int64) error) (int, error) {
+	ids, err := parseIDs(args)
+	if err != nil {
+		return exitCode(err), err
+	}
+	if len(ids) == 0 {
+		return exitUsage, newUsageError("Usage: prtgctl %v", commands[name].usage)
+	}
+	code := exitOK
+	for _, id := range ids {
+		if err := action(id); err != nil {
+			fmt.Fprintf(e.stderr, "%v %v: %v\n", name, id, err)
+			code = exitUnknown
+			continue
+		}
+		fmt.Fprintf(e.stdout, "%v %v: done\n", name, id)
+	}
+	return code, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// config contains PRTG's server and credentials.
+// The value is taken from the flags, then the environment variables, then the config file.
+type config struct {
+	Server       string `json:"server"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	PasswordHash string `json:"passhash"`
+}
+
+// Environment variables of config
+const (
+	envServer       = "PRTG_SERVER"
+	envUsername     = "PRTG_USERNAME"
+	envPassword     = "PRTG_PASSWORD"
+	envPasswordHash = "PRTG_PASSHASH"
+	envConfig       = "PRTGCTL_CONFIG"
+)
+
+// defaultConfigPath returns $XDG_CONFIG_HOME/prtgctl/config.json or its equivalent.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "prtgctl", "config.json")
+}
+
+// loadConfigFile reads the JSON config file.
+// The missing file is ignored unless it's explicitly specified.
+func loadConfigFile(path string, explicit bool) (config, error) {
+	var cfg config
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("Unable to read config file: %v", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("Unable to parse config file %v: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// merge fills the empty values of cfg with the other's values.
+func (cfg *config) merge(other config) {
+	if cfg.Server == "" {
+		cfg.Server = other.Server
+	}
+	if cfg.Username == "" {
+		cfg.Username = other.Username
+	}
+	if cfg.Password == "" && cfg.PasswordHash == "" {
+		cfg.Password = other.Password
+		cfg.PasswordHash = other.PasswordHash
+	}
+}
+
+func (cfg *config) validate() error {
+	if cfg.Server == "" || cfg.Username == "" {
+		return fmt.Errorf("Server and username are mandatory")
+	}
+	if cfg.Password == "" && cfg.PasswordHash == "" {
+		return fmt.Errorf("Either password or passhash is mandatory")
+	}
+	return nil
+}
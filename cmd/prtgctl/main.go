@@ -0,0 +1,241 @@
+// Command prtgctl queries and controls PRTG from the command line.
+//
+// Usage:
+//
+//	prtgctl [global flags] <command> [command flags] [arguments]
+//
+// The credentials are taken from the global flags, then PRTG_SERVER, PRTG_USERNAME,
+// PRTG_PASSWORD, and PRTG_PASSHASH environment variables, then the JSON config file.
+//
+// The exit code of sensors and detail commands reflects the worst sensor's state:
+// 0 when every sensor is up or paused, 1 for warning, 2 for down, and 3 for unknown.
+// Any failure exits with 3 as well, and invalid usage exits with 64.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+// Exit codes, following the monitoring plugin's convention
+const (
+	exitOK       = 0
+	exitWarning  = 1
+	exitCritical = 2
+	exitUnknown  = 3
+	exitUsage    = 64
+)
+
+// env contains the process's streams and environment, so commands can be tested.
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+}
+
+// command runs with its own arguments and returns the exit code.
+type command struct {
+	usage       string
+	description string
+	run         func(e *env, client *prtg.Client, output string, args []string) (int, error)
+}
+
+var commands map[string]command
+
+// The commands are registered in init, since some of them print their own usage.
+func init() {
+	commands = map[string]command{
+		"version": {"version", "Print PRTG's version.", runVersion},
+		"sensors": {"sensors [id]", "List sensors within the object, default to root.", runSensors},
+		"devices": {"devices [id]", "List devices within the object, default to root.", runDevices},
+		"groups":  {"groups [id]", "List groups within the object, default to root.", runGroups},
+		"tree":    {"tree [id]", "Print the sensor tree within the object, default to root.", runTree},
+		"detail":  {"detail <id>", "Print the detail of sensor, device, or group.", runDetail},
+		"history": {"history [-since 24h | -start time -end time] [-avg seconds] [-raw] <id>", "Print the historic data of sensor.", runHistory},
+		"pause":   {"pause [-message text] [-duration 1h] <id>...", "Pause the objects.", runPause},
+		"resume":  {"resume <id>...", "Resume the paused objects.", runResume},
+		"ack":     {"ack [-message text] <id>...", "Acknowledge the alarm of down sensors.", runAck},
+	}
+}
+
+func main() {
+	e := &env{stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	os.Exit(run(e, os.Args[1:]))
+}
+
+func run(e *env, args []string) int {
+	flags := flag.NewFlagSet("prtgctl", flag.ContinueOnError)
+	flags.SetOutput(e.stderr)
+	var (
+		configPath = flags.String("config", "", "JSON config file, defaults to $"+envConfig+" or "+defaultConfigPath()+".")
+		server     = flags.String("server", "", "PRTG's server URL, e.g. https://prtg.example.com.")
+		username   = flags.String("username", "", "PRTG's username.")
+		password   = flags.String("password", "", "PRTG's password.")
+		passHash   = flags.String("passhash", "", "PRTG's password hash.")
+		output     = flags.String("output", outputTable, "Output format: table, json, or csv.")
+		timeout    = flags.Duration("timeout", 10*time.Second, "Timeout of each request to PRTG.")
+	)
+	flags.Usage = func() { printUsage(e.stderr, flags) }
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(e.stderr, "Unknown command %q\n", flags.Arg(0))
+		flags.Usage()
+		return exitUsage
+	}
+	if err := validateOutput(*output); err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return exitUsage
+	}
+
+	// Flags, then environment variables, then config file
+	cfg := config{Server: *server, Username: *username, Password: *password, PasswordHash: *passHash}
+	cfg.merge(config{
+		Server:       e.getenv(envServer),
+		Username:     e.getenv(envUsername),
+		Password:     e.getenv(envPassword),
+		PasswordHash: e.getenv(envPasswordHash),
+	})
+	explicit := true
+	if *configPath == "" {
+		*configPath = e.getenv(envConfig)
+	}
+	if *configPath == "" {
+		*configPath, explicit = defaultConfigPath(), false
+	}
+	fileCfg, err := loadConfigFile(*configPath, explicit)
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return exitUnknown
+	}
+	cfg.merge(fileCfg)
+	if err := cfg.validate(); err != nil {
+		fmt.Fprintln(e.stderr, err)
+		return exitUsage
+	}
+
+	var client *prtg.Client
+	if cfg.Password != "" {
+		client = prtg.NewClient(cfg.Server, cfg.Username, cfg.Password)
+	} else {
+		client = prtg.NewClientWithHashedPass(cfg.Server, cfg.Username, cfg.PasswordHash)
+	}
+	client.SetContextTimeout(timeout.Milliseconds())
+
+	code, err := cmd.run(e, client, *output, flags.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(e.stderr, err)
+	}
+	return code
+}
+
+func printUsage(w io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintln(w, "Usage: prtgctl [global flags] <command> [command flags] [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %v\n    \t%v\n", commands[name].usage, commands[name].description)
+	}
+	fmt.Fprintln(w, "\nGlobal flags:")
+	flags.PrintDefaults()
+	fmt.Fprintln(w, "\nExit codes: 0 ok, 1 warning, 2 down, 3 unknown or failure, 64 invalid usage.")
+}
+
+// sensorState returns the exit code of PRTG's raw status.
+func sensorState(statusRaw int64) int {
+	switch statusRaw {
+	case 2, 3, 7, 8, 9, 11, 12:
+		// Scanning, Up, Paused, Not Licensed, and Paused Until
+		return exitOK
+	case 4, 10, 13, 14:
+		// Warning, Unusual, Down Acknowledged, and Down Partial
+		return exitWarning
+	case 5:
+		return exitCritical
+	}
+	// Unknown, No Probe, and the others
+	return exitUnknown
+}
+
+// worseState returns the worse of two exit codes, down being the worst.
+func worseState(a, b int) int {
+	rank := map[int]int{exitOK: 0, exitWarning: 1, exitUnknown: 2, exitCritical: 3}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// newFlagSet returns the command's flag set, printing its usage on error.
+func newFlagSet(e *env, name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(e.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: prtgctl %v\n", commands[name].usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// usageError marks the invalid arguments of a command.
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func newUsageError(format string, a ...interface{}) error {
+	return &usageError{msg: fmt.Sprintf(format, a...)}
+}
+
+// exitCode returns the code of failed command.
+func exitCode(err error) int {
+	if _, ok := err.(*usageError); ok {
+		return exitUsage
+	}
+	return exitUnknown
+}
+
+func parseIDs(args []string) ([]int64, error) {
+	ids := make([]int64, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || id < 0 {
+			return nil, newUsageError("Invalid object id %q", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseOptionalID returns the only id argument, or zero (root) if there is none.
+func parseOptionalID(args []string) (int64, error) {
+	if len(args) > 1 {
+		return 0, newUsageError("Too many arguments: %v", strings.Join(args, " "))
+	}
+	ids, err := parseIDs(args)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
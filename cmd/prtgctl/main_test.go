@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+func loadfixture(f string) string {
+	pwd, _ := os.Getwd()
+	p := filepath.Join(pwd, "..", "..", "fixtures", f)
+	c, _ := ioutil.ReadFile(p)
+	return string(c)
+}
+
+func setup() *httptest.Server {
+	mux := new(http.ServeMux)
+	mux.HandleFunc(prtg.GetSensorDetailsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.FormValue("id") == "0" {
+			fmt.Fprint(w, loadfixture("/prtg_version.json"))
+		} else {
+			fmt.Fprint(w, loadfixture("/prtg_sensor_9182.json"))
+		}
+	})
+	mux.HandleFunc(prtg.GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		switch r.FormValue("id") {
+		case "9301":
+			fmt.Fprint(w, loadfixture("/prtg_sensor-list_9301.json"))
+		case "9000":
+			fmt.Fprint(w, loadfixture("/prtg_sensor-list_9000_empty.json"))
+		}
+	})
+	mux.HandleFunc(prtg.GetSensorTreesEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_sensortree_device_9200.xml"))
+	})
+	mux.HandleFunc(prtg.PauseObjectEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("id") == "9000" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	return httptest.NewServer(mux)
+}
+
+func runTest(server string, environ map[string]string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	e := &env{
+		stdout: &stdout,
+		stderr: &stderr,
+		getenv: func(key string) string {
+			if key == envServer && server != "" {
+				return server
+			}
+			return environ[key]
+		},
+	}
+	code := run(e, args)
+	return code, stdout.String(), stderr.String()
+}
+
+var testCredentials = map[string]string{envUsername: "user", envPassword: "pass"}
+
+func TestRunCommands(t *testing.T) {
+	httpServer := setup()
+	defer httpServer.Close()
+
+	testCases := []struct {
+		args     []string
+		code     int
+		contains string
+	}{
+		{[]string{"version"}, exitOK, "18.2.41.1636"},
+		{[]string{"-output", "json", "version"}, exitOK, `"version": "18.2.41.1636"`},
+		// Every sensor is paused
+		{[]string{"sensors", "9301"}, exitOK, "Paused by parent"},
+		{[]string{"-output", "csv", "sensors", "9301"}, exitOK, "id,probe,group,device,sensor,status,lastvalue,message\r\n9302,"},
+		{[]string{"sensors", "9000"}, exitOK, "ID"},
+		{[]string{"-output", "csv", "tree", "9200"}, exitOK, "sensor,9201,CBQoS DATA-FILETRANSFER (matchAny) (Main Interface:2 output)[Class Map],Cisco CBQoS,Paused"},
+		// Sensor's status is unknown
+		{[]string{"detail", "9182"}, exitUnknown, "Firewall"},
+		{[]string{"pause", "-message", "maintenance", "9201", "9202"}, exitOK, "pause 9202: done"},
+		{[]string{"resume", "9000"}, exitUnknown, ""},
+		{[]string{"detail"}, exitUsage, ""},
+		{[]string{"sensors", "abc"}, exitUsage, ""},
+		{[]string{"-output", "yaml", "version"}, exitUsage, ""},
+		{[]string{"unknown"}, exitUsage, ""},
+		{nil, exitUsage, ""},
+	}
+	for _, tc := range testCases {
+		code, stdout, stderr := runTest(httpServer.URL, testCredentials, tc.args...)
+		if code != tc.code {
+			t.Errorf("%v exits with %v instead of %v: %v", tc.args, code, tc.code, stderr)
+		}
+		if !strings.Contains(stdout, tc.contains) {
+			t.Errorf("%v prints %q, it should contain %q", tc.args, stdout, tc.contains)
+		}
+	}
+}
+
+func TestConfigFile(t *testing.T) {
+	httpServer := setup()
+	defer httpServer.Close()
+
+	dir, err := ioutil.TempDir("", "prtgctl")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	data, _ := json.Marshal(config{Server: httpServer.URL, Username: "user", PasswordHash: "000000000"})
+	ioutil.WriteFile(path, data, 0600)
+
+	// Credentials from the config file
+	code, stdout, stderr := runTest("", nil, "-config", path, "version")
+	if code != exitOK || !strings.Contains(stdout, "18.2.41.1636") {
+		t.Errorf("It should be success but exits with %v: %v", code, stderr)
+	}
+	// Flags override the config file
+	code, _, _ = runTest("", nil, "-config", path, "-server", "http://127.0.0.1:1", "version")
+	if code != exitUnknown {
+		t.Errorf("It should fail to connect, but exits with %v", code)
+	}
+	// Missing explicit config file
+	code, _, _ = runTest("", nil, "-config", filepath.Join(dir, "missing.json"), "version")
+	if code != exitUnknown {
+		t.Errorf("It should fail to read the config file, but exits with %v", code)
+	}
+	// Missing credentials
+	code, _, _ = runTest(httpServer.URL, map[string]string{envConfig: filepath.Join(dir, "missing.json")}, "version")
+	if code == exitOK {
+		t.Errorf("It should fail without credentials")
+	}
+}
+
+func TestSensorState(t *testing.T) {
+	if code := worseState(sensorState(3), sensorState(4)); code != exitWarning {
+		t.Errorf("Up and Warning should be warning instead of %v", code)
+	}
+	if code := worseState(sensorState(5), sensorState(1)); code != exitCritical {
+		t.Errorf("Down and Unknown should be critical instead of %v", code)
+	}
+	if code := worseState(sensorState(7), sensorState(1)); code != exitUnknown {
+		t.Errorf("Paused and Unknown should be unknown instead of %v", code)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+)
+
+// result contains the rows for table and CSV output, and the value for JSON output.
+type result struct {
+	columns []string
+	rows    [][]string
+	value   interface{}
+}
+
+func validateOutput(format string) error {
+	switch format {
+	case outputTable, outputJSON, outputCSV:
+		return nil
+	}
+	return fmt.Errorf("Unknown output format %q, it should be table, json, or csv", format)
+}
+
+func writeResult(w io.Writer, format string, r *result) error {
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r.value)
+	case outputCSV:
+		cw := csv.NewWriter(w)
+		cw.UseCRLF = true
+		if err := cw.Write(r.columns); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(r.rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(r.columns, "\t")))
+	for _, row := range r.rows {
+		fmt.Fprintln(tw, strings.Join(sanitizeCells(row), "\t"))
+	}
+	return tw.Flush()
+}
+
+// sanitizeCells keeps each cell on a single line of the table.
+func sanitizeCells(row []string) []string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		cells[i] = strings.Join(strings.Fields(cell), " ")
+	}
+	return cells
+}
@@ -0,0 +1,147 @@
+package prtg
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	pauseAction  = "0"
+	resumeAction = "1"
+)
+
+// requestAction calls PRTG's API which changes an object.
+// The response is never cached, and the cached responses are dropped afterward.
+func (c *Client) requestAction(p string, q *url.Values) error {
+	u, err := c.getCompleteUrl(p, q)
+	if err != nil {
+		return err
+	}
+	_, _, err = c.getHTTPBody(p, q, u)
+	if err != nil {
+		return err
+	}
+	c.InvalidateCache()
+	return nil
+}
+
+// PauseObject pauses the specified sensor, device, group, or probe indefinitely.
+// The message is shown as the object's status message.
+func (c *Client) PauseObject(id int64, message string) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return fmt.Errorf("Id should be more than or equals to zero")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("action", pauseAction)
+	if message != "" {
+		q.Set("pausemsg", message)
+	}
+	if err := c.requestAction(PauseObjectEndpoint, q); err != nil {
+		return fmt.Errorf("Unable to pause object: %v", err)
+	}
+	return nil
+}
+
+// PauseObjectFor pauses the specified object for the duration, rounded up to minute.
+// PRTG resumes the object afterward.
+func (c *Client) PauseObjectFor(id int64, message string, duration time.Duration) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return fmt.Errorf("Id should be more than or equals to zero")
+	}
+	if duration <= 0 {
+		return fmt.Errorf("Duration should be more than zero")
+	}
+
+	minutes := int64((duration + time.Minute - 1) / time.Minute)
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("duration", fmt.Sprintf("%v", minutes))
+	if message != "" {
+		q.Set("pausemsg", message)
+	}
+	if err := c.requestAction(PauseObjectForEndpoint, q); err != nil {
+		return fmt.Errorf("Unable to pause object: %v", err)
+	}
+	return nil
+}
+
+// ResumeObject resumes the specified paused object.
+func (c *Client) ResumeObject(id int64) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return fmt.Errorf("Id should be more than or equals to zero")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("action", resumeAction)
+	if err := c.requestAction(PauseObjectEndpoint, q); err != nil {
+		return fmt.Errorf("Unable to resume object: %v", err)
+	}
+	return nil
+}
+
+// AcknowledgeAlarm acknowledges the alarm of the specified sensor in down state.
+func (c *Client) AcknowledgeAlarm(id int64, message string) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return fmt.Errorf("Id should be more than or equals to zero")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	if message != "" {
+		q.Set("ackmsg", message)
+	}
+	if err := c.requestAction(AcknowledgeAlarmEndpoint, q); err != nil {
+		return fmt.Errorf("Unable to acknowledge alarm: %v", err)
+	}
+	return nil
+}
+
+// MoveObject moves the device or group into the target group or probe.
+func (c *Client) MoveObject(id, targetID int64) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 || targetID < 0 {
+		return fmt.Errorf("Id should be more than or equals to zero")
+	}
+	if id == targetID {
+		return fmt.Errorf("Object can't be moved into itself")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("targetid", fmt.Sprintf("%v", targetID))
+	if err := c.requestAction(MoveObjectEndpoint, q); err != nil {
+		return fmt.Errorf("Unable to move object: %v", err)
+	}
+	return nil
+}
+
+// DeleteObject deletes the sensor, device, group, or probe, together with the objects within it.
+// It can't be undone.
+func (c *Client) DeleteObject(id int64) error {
+	// Validate input
+	// The root group can't be deleted
+	if id <= 0 {
+		return fmt.Errorf("Id should be more than zero")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("approve", "1")
+	if err := c.requestAction(DeleteObjectEndpoint, q); err != nil {
+		return fmt.Errorf("Unable to delete object: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package prtg
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestObjectActions(t *testing.T) {
+	var lastQuery url.Values
+	var lastPath string
+	mux := new(http.ServeMux)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		lastPath = r.URL.Path
+		lastQuery = r.URL.Query()
+		if lastQuery.Get("id") == "9000" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "<html></html>")
+	}
+	mux.HandleFunc(PauseObjectEndpoint, handler)
+	mux.HandleFunc(PauseObjectForEndpoint, handler)
+	mux.HandleFunc(AcknowledgeAlarmEndpoint, handler)
+	mux.HandleFunc(MoveObjectEndpoint, handler)
+	mux.HandleFunc(DeleteObjectEndpoint, handler)
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	client := NewClient(fmt.Sprintf("%v", serverURL), "user", "pass")
+
+	// Pause
+	if err := client.PauseObject(9201, "maintenance"); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if lastPath != PauseObjectEndpoint || lastQuery.Get("action") != "0" || lastQuery.Get("pausemsg") != "maintenance" {
+		t.Errorf("Unexpected pause request: %v?%v", lastPath, lastQuery.Encode())
+	}
+
+	// Pause for a duration, rounded up to minute
+	if err := client.PauseObjectFor(9201, "", 90*time.Second); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if lastPath != PauseObjectForEndpoint || lastQuery.Get("duration") != "2" {
+		t.Errorf("Unexpected pause request: %v?%v", lastPath, lastQuery.Encode())
+	}
+	if err := client.PauseObjectFor(9201, "", 0); err == nil {
+		t.Errorf("Since the duration is zero, an error should occur.")
+	}
+
+	// Resume
+	if err := client.ResumeObject(9201); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if lastPath != PauseObjectEndpoint || lastQuery.Get("action") != "1" {
+		t.Errorf("Unexpected resume request: %v?%v", lastPath, lastQuery.Encode())
+	}
+
+	// Acknowledge
+	if err := client.AcknowledgeAlarm(9201, "on it"); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if lastPath != AcknowledgeAlarmEndpoint || lastQuery.Get("ackmsg") != "on it" {
+		t.Errorf("Unexpected acknowledge request: %v?%v", lastPath, lastQuery.Encode())
+	}
+
+	// Move
+	if err := client.MoveObject(9201, 9178); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if lastPath != MoveObjectEndpoint || lastQuery.Get("targetid") != "9178" {
+		t.Errorf("Unexpected move request: %v?%v", lastPath, lastQuery.Encode())
+	}
+	if err := client.MoveObject(9201, 9201); err == nil {
+		t.Errorf("Since the object is moved into itself, an error should occur.")
+	}
+
+	// Delete
+	if err := client.DeleteObject(9201); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if lastPath != DeleteObjectEndpoint || lastQuery.Get("approve") != "1" {
+		t.Errorf("Unexpected delete request: %v?%v", lastPath, lastQuery.Encode())
+	}
+	if err := client.DeleteObject(0); err == nil {
+		t.Errorf("Since the root can't be deleted, an error should occur.")
+	}
+
+	// PRTG's error
+	if err := client.ResumeObject(9000); err == nil {
+		t.Errorf("Since PRTG responds with bad request, an error should occur.")
+	}
+	// id should be more than or equals to zero
+	if err := client.AcknowledgeAlarm(-1, ""); err == nil {
+		t.Errorf("Since the id is less than zero, an error should occur.")
+	}
+}
+
+func TestObjectActionInvalidatesCache(t *testing.T) {
+	count := 0
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetSensorDetailsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_sensor_9182.json"))
+	})
+	mux.HandleFunc(PauseObjectEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	client := NewClient(fmt.Sprintf("%v", serverURL), "user", "pass")
+	client.EnableCache(CacheConfig{DefaultTTL: time.Minute})
+
+	client.GetSensorDetail(9182)
+	client.GetSensorDetail(9182)
+	if count != 1 {
+		t.Errorf("The detail should be requested once instead of %v", count)
+	}
+	if err := client.PauseObject(9182, ""); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	client.GetSensorDetail(9182)
+	if count != 2 {
+		t.Errorf("The detail should be requested again after pausing, but requested %v times", count)
+	}
+}
@@ -1,5 +1,10 @@
 package prtg
 
+import (
+	"strconv"
+	"time"
+)
+
 type prtgSensorDetailsResponse struct {
 	PrtgVersion string         `json:"prtgversion" xml:"prtg-version"`
 	SensorData  PrtgSensorData `json:"sensordata"`
@@ -36,12 +41,45 @@ type PrtgSensorData struct {
 	Info             string `json:"info" xml:"info"`
 }
 
+// PrtgSensorDataTyped contains the typed property of PrtgSensorData.
+// It's decoded consistently from both JSON and XML response, either directly or through PrtgSensorData.Typed.
+type PrtgSensorDataTyped struct {
+	Name             string
+	SensorType       string
+	Interval         time.Duration
+	ProbeName        string
+	ParentGroupName  string
+	ParentDeviceName string
+	ParentDeviceID   int64
+	LastValue        string
+	LastMessage      string
+	Favorite         bool
+	StatusText       string
+	StatusID         int64
+	LastUp           time.Time
+	LastDown         time.Time
+	LastCheck        time.Time
+	// Uptime in percent
+	Uptime       float64
+	UptimeTime   string
+	Downtime     float64
+	DowntimeTime string
+	UpDownTotal  string
+	UpDownSince  time.Time
+	Info         string
+}
+
 type prtgTableListResponse struct {
-	PrtgVersion string          `json:"prtgversion" xml:"prtg-version"`
-	TreeSize    int64           `json:"treesize" xml:"treesize"`
-	Groups      []PrtgTableList `json:"groups, xml:"groups,omitempty"`
-	Devices     []PrtgTableList `json:"devices, xml:"devices,omitempty"`
-	Sensors     []PrtgTableList `json:"sensors, xml:"sensors,omitempty"`
+	PrtgVersion   string                `json:"prtgversion" xml:"prtg-version"`
+	TreeSize      int64                 `json:"treesize" xml:"treesize"`
+	Groups        []PrtgTableList       `json:"groups" xml:"groups,omitempty"`
+	Devices       []PrtgTableList       `json:"devices" xml:"devices,omitempty"`
+	Sensors       []PrtgTableList       `json:"sensors" xml:"sensors,omitempty"`
+	Channels      []PrtgChannelList     `json:"channels" xml:"channels,omitempty"`
+	Reports       []PrtgReport          `json:"reports" xml:"reports,omitempty"`
+	Triggers      []prtgTriggerRow      `json:"triggers" xml:"-"`
+	Notifications []prtgNotificationRow `json:"notifications" xml:"-"`
+	Messages      []prtgMessageRow      `json:"messages" xml:"-"`
 }
 
 // PrtgTableList contains property for each sensor, device, and group object within list API.
@@ -53,6 +91,10 @@ type PrtgTableList struct {
 	Device             string `json:"device" xml:"device"`
 	Host               string `json:"host" xml:"host"`
 	Sensor             string `json:"sensor" xml:"sensor"`
+	Status             string `json:"status" xml:"status"`
+	StatusRaw          int64  `json:"status_raw" xml:"status_raw"`
+	Message            string `json:"message_raw" xml:"message_raw"`
+	LastValue          string `json:"lastvalue" xml:"lastvalue"`
 	DownSensors        int64  `json:"downsens_raw" xml:"downsens_raw"`
 	PartialDownSensors int64  `json:"partialdownsens_raw" xml:"partialdownsens_raw"`
 	DownAckSensors     int64  `json:"downacksens_raw" xml:"downacksens_raw"`
@@ -64,6 +106,57 @@ type PrtgTableList struct {
 	Type               string `json:"type_raw" xml:"type_raw"`
 }
 
+// PrtgChannelList contains property for each channel of a sensor within list API.
+type PrtgChannelList struct {
+	ObjectId  int64  `json:"objid" xml:"objid"`
+	Name      string `json:"name" xml:"name"`
+	LastValue string `json:"lastvalue" xml:"lastvalue"`
+	// PRTG returns empty string instead of number if the channel has no data
+	LastValueRaw interface{} `json:"lastvalue_raw" xml:"lastvalue_raw"`
+}
+
+// RawValue returns the channel's last raw value, and false if the channel has no data.
+func (ch *PrtgChannelList) RawValue() (float64, bool) {
+	switch v := ch.LastValueRaw.(type) {
+	case float64:
+		return v, true
+	case string:
+		value, err := strconv.ParseFloat(trimWeirdCharacter(v), 64)
+		return value, err == nil
+	}
+	return 0, false
+}
+
+// PrtgReport contains property for each report within list API.
+type PrtgReport struct {
+	ObjectId int64  `json:"objid" xml:"objid"`
+	Name     string `json:"name" xml:"name"`
+	Template string `json:"template" xml:"template"`
+	Period   string `json:"period" xml:"period"`
+	Schedule string `json:"schedule" xml:"schedule"`
+	Email    string `json:"email" xml:"email"`
+	LastRun  string `json:"lastrun" xml:"lastrun"`
+	// PRTG returns empty string instead of number if the report has never run
+	LastRunRaw interface{} `json:"lastrun_raw" xml:"lastrun_raw"`
+}
+
+// LastRunTime returns the time the report last ran, and false if it has never run.
+func (r *PrtgReport) LastRunTime() (time.Time, bool) {
+	var days float64
+	switch v := r.LastRunRaw.(type) {
+	case float64:
+		days = v
+	case string:
+		var err error
+		if days, err = strconv.ParseFloat(trimWeirdCharacter(v), 64); err != nil {
+			return time.Time{}, false
+		}
+	default:
+		return time.Time{}, false
+	}
+	return ConvertPrtgDateTime(days), true
+}
+
 type prtgHistoricDataResponse struct {
 	PrtgVersion  string             `json:"prtgversion" xml:"prtg-version"`
 	TreeSize     int64              `json:"treesize" xml:"treesize"`
@@ -84,7 +177,7 @@ type ItemTagXML struct {
 	DatetimeRAW string     `xml:"datetime_raw"`
 	Coverage    string     `xml:"coverage"`
 	CoverageRAW string     `xml:"coverage_raw"`
-	Value       []ValueXML `xml:"value"XML`
+	Value       []ValueXML `xml:"value"`
 	ValueRAW    []ValueXML `xml:"value_raw"`
 }
 
@@ -143,6 +236,7 @@ type SensorTreeSensor struct {
 	SensorKind              string  `xml:"sensorkind"`
 	SensorInterval          int64   `xml:"interval"`
 	SensorStatus            string  `xml:"status"`
+	SensorStatusRaw         int64   `xml:"status_raw"`
 	SensorLastValue         float64 `xml:"lastvalue_raw"`
 	SensorStatusMessage     string  `xml:"statusmessage"`
 	SensorStatusSince       float64 `xml:"statussince_raw_utc"`
@@ -0,0 +1,159 @@
+package prtg
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// CacheConfig contains the time-to-live of cached responses.
+type CacheConfig struct {
+	// Time-to-live for every endpoint which is not listed in EndpointTTL.
+	// Zero value means that the response is not stored,
+	// but concurrent identical requests are still de-duplicated.
+	DefaultTTL time.Duration
+
+	// Time-to-live per endpoint path, e.g. GetSensorTreesEndpoint.
+	EndpointTTL map[string]time.Duration
+}
+
+type cacheEntry struct {
+	body    []byte
+	header  http.Header
+	expires time.Time
+}
+
+type cacheCall struct {
+	wg     sync.WaitGroup
+	body   []byte
+	header http.Header
+	err    error
+}
+
+type responseCache struct {
+	mu         sync.Mutex
+	config     CacheConfig
+	entries    map[string]*cacheEntry
+	calls      map[string]*cacheCall
+	generation uint64
+	now        func() time.Time
+}
+
+var credentialQueryKeys = []string{"username", "password", "passhash"}
+
+func newResponseCache(config CacheConfig) *responseCache {
+	return &responseCache{
+		config:  config,
+		entries: map[string]*cacheEntry{},
+		calls:   map[string]*cacheCall{},
+		now:     time.Now,
+	}
+}
+
+// EnableCache turns on the response cache of the client.
+// Responses are keyed by server, endpoint, and query, excluding the credentials.
+// It should be called before the client is shared between goroutines.
+func (c *Client) EnableCache(config CacheConfig) {
+	c.cache = newResponseCache(config)
+}
+
+// DisableCache turns off the response cache and drops every cached response.
+func (c *Client) DisableCache() {
+	c.cache = nil
+}
+
+// InvalidateCache drops every cached response.
+// It should be called after any action which changes PRTG's objects, e.g. pausing a sensor.
+func (c *Client) InvalidateCache() {
+	if c.cache == nil {
+		return
+	}
+	c.cache.invalidate()
+}
+
+func (c *Client) getCacheKey(p string, q *url.Values) string {
+	keyQuery := url.Values{}
+	for k, v := range *q {
+		keyQuery[k] = v
+	}
+	for _, k := range credentialQueryKeys {
+		keyQuery.Del(k)
+	}
+	return c.Server + p + "?" + keyQuery.Encode()
+}
+
+func (rc *responseCache) getTTL(p string) time.Duration {
+	if ttl, ok := rc.config.EndpointTTL[p]; ok {
+		return ttl
+	}
+	return rc.config.DefaultTTL
+}
+
+func (rc *responseCache) invalidate() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.entries = map[string]*cacheEntry{}
+	rc.generation++
+}
+
+// getHTTPBody returns the cached response of key, or calls fetch once
+// for every concurrent caller asking the same key.
+func (rc *responseCache) getHTTPBody(key, p string, fetch func() ([]byte, *http.Header, error)) ([]byte, *http.Header, error) {
+	rc.mu.Lock()
+	if entry, ok := rc.entries[key]; ok {
+		if rc.now().Before(entry.expires) {
+			rc.mu.Unlock()
+			return copyCachedResponse(entry.body, entry.header)
+		}
+		delete(rc.entries, key)
+	}
+	if call, ok := rc.calls[key]; ok {
+		rc.mu.Unlock()
+		call.wg.Wait()
+		if call.err != nil {
+			return nil, nil, call.err
+		}
+		return copyCachedResponse(call.body, call.header)
+	}
+	call := new(cacheCall)
+	call.wg.Add(1)
+	rc.calls[key] = call
+	generation := rc.generation
+	rc.mu.Unlock()
+
+	body, header, err := fetch()
+	call.body = body
+	call.err = err
+	if header != nil {
+		call.header = *header
+	}
+
+	rc.mu.Lock()
+	delete(rc.calls, key)
+	// Responses fetched before an invalidation may be stale, so they are not stored.
+	if ttl := rc.getTTL(p); err == nil && ttl > 0 && generation == rc.generation {
+		rc.entries[key] = &cacheEntry{
+			body:    call.body,
+			header:  call.header,
+			expires: rc.now().Add(ttl),
+		}
+	}
+	rc.mu.Unlock()
+	call.wg.Done()
+
+	if err != nil {
+		return nil, nil, err
+	}
+	return copyCachedResponse(call.body, call.header)
+}
+
+func copyCachedResponse(body []byte, header http.Header) ([]byte, *http.Header, error) {
+	bodyCopy := make([]byte, len(body))
+	copy(bodyCopy, body)
+	headerCopy := header.Clone()
+	if headerCopy == nil {
+		headerCopy = http.Header{}
+	}
+	return bodyCopy, &headerCopy, nil
+}
@@ -0,0 +1,189 @@
+package prtg
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheGetSensorTree(t *testing.T) {
+	var hits int64
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetSensorTreesEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		responsesXmlOk(w, "/prtg_sensortree_device_9200.xml")
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	server := fmt.Sprintf("%v", serverURL)
+	client := NewClient(server, "user", "pass")
+	client.EnableCache(CacheConfig{DefaultTTL: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		sensorTree, err := client.GetSensorTree(9200)
+		if err != nil {
+			t.Errorf("It should be success but error: %v", err)
+			return
+		}
+		if len(sensorTree.Devices) != 1 {
+			t.Errorf("The content of devices should be 1, but %v device(s) found", len(sensorTree.Devices))
+		}
+	}
+	if hits != 1 {
+		t.Errorf("Server should be hit once, but %v hit(s)", hits)
+	}
+
+	// Invalidation should force the next call to hit the server
+	client.InvalidateCache()
+	_, err := client.GetSensorTree(9200)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if hits != 2 {
+		t.Errorf("Server should be hit twice after invalidation, but %v hit(s)", hits)
+	}
+
+	// Disabled cache should always hit the server
+	client.DisableCache()
+	_, err = client.GetSensorTree(9200)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if hits != 3 {
+		t.Errorf("Server should be hit thrice after disabling cache, but %v hit(s)", hits)
+	}
+}
+
+func TestCacheEndpointTTL(t *testing.T) {
+	var hits int64
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_device-list_9217.json"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	server := fmt.Sprintf("%v", serverURL)
+	client := NewClient(server, "user", "pass")
+	client.EnableCache(CacheConfig{
+		DefaultTTL:  0,
+		EndpointTTL: map[string]time.Duration{GetTableListsEndpoint: time.Minute},
+	})
+	now := time.Date(2018, time.May, 1, 0, 0, 0, 0, time.UTC)
+	client.cache.now = func() time.Time { return now }
+
+	_, err := client.GetDeviceList(9217, nil)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	_, err = client.GetDeviceList(9217, nil)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("Server should be hit once, but %v hit(s)", hits)
+	}
+
+	// Different query should not share the cached response
+	_, err = client.GetDeviceList(9217, []string{"objid", "device"})
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if hits != 2 {
+		t.Errorf("Server should be hit twice for different columns, but %v hit(s)", hits)
+	}
+
+	// Expired response should be fetched again
+	now = now.Add(2 * time.Minute)
+	_, err = client.GetDeviceList(9217, nil)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if hits != 3 {
+		t.Errorf("Server should be hit thrice after expiration, but %v hit(s)", hits)
+	}
+}
+
+func TestCacheKeyExcludesCredentials(t *testing.T) {
+	client := NewClient("http://localhost", "user", "secret")
+	q := client.getTemplateUrlQuery()
+	q.Set("id", "0")
+	key := client.getCacheKey(GetSensorDetailsEndpoint, q)
+	if key != "http://localhost/api/getsensordetails.json?id=0" {
+		t.Errorf("Cache key is %v instead of http://localhost/api/getsensordetails.json?id=0", key)
+	}
+
+	hashedClient := NewClientWithHashedPass("http://localhost", "user", "000000000")
+	q = hashedClient.getTemplateUrlQuery()
+	q.Set("id", "0")
+	if hashedKey := hashedClient.getCacheKey(GetSensorDetailsEndpoint, q); hashedKey != key {
+		t.Errorf("Cache key is %v instead of %v", hashedKey, key)
+	}
+}
+
+func TestCacheSingleflight(t *testing.T) {
+	var hits int64
+	release := make(chan struct{})
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_device-list_9217.json"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	server := fmt.Sprintf("%v", serverURL)
+	client := NewClient(server, "user", "pass")
+	// Zero TTL, only de-duplication is expected
+	client.EnableCache(CacheConfig{})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetDeviceList(9217, nil)
+			errs <- err
+		}()
+	}
+	// Wait until the single flight reaches the server
+	for atomic.LoadInt64(&hits) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("It should be success but error: %v", err)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("Server should be hit once for concurrent identical requests, but %v hit(s)", hits)
+	}
+
+	// Zero TTL should not keep the response
+	_, err := client.GetDeviceList(9217, nil)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if hits != 2 {
+		t.Errorf("Server should be hit twice since zero TTL, but %v hit(s)", hits)
+	}
+}
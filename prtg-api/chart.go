@@ -0,0 +1,190 @@
+package prtg
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChartFormat is the image format of a chart.
+type ChartFormat int
+
+// Chart formats
+const (
+	ChartPNG ChartFormat = iota
+	ChartSVG
+)
+
+// ChartGraph is PRTG's predefined graph of a sensor.
+type ChartGraph int
+
+// Chart graphs. ChartGraphCustom covers the requested start and end,
+// and the others cover their period up to now.
+const (
+	ChartGraphCustom ChartGraph = iota
+	ChartGraphLive
+	ChartGraph48Hours
+	ChartGraph30Days
+	ChartGraph365Days
+)
+
+// ChartTheme is the chart's colors.
+type ChartTheme int
+
+// Chart themes
+const (
+	ChartThemeLight ChartTheme = iota
+	ChartThemeDark
+)
+
+var chartBackgrounds = map[ChartTheme]string{
+	ChartThemeLight: "#ffffff",
+	ChartThemeDark:  "#1e1e1e",
+}
+
+var (
+	defaultChartWidth    = 850
+	defaultChartHeight   = 270
+	defaultChartFontSize = 10
+)
+
+// ChartOptions customizes the chart, nil means the default values.
+type ChartOptions struct {
+	Format ChartFormat
+	// Graph defaults to ChartGraphCustom
+	Graph ChartGraph
+	// Width and Height in pixel, default to 850 x 270
+	Width  int
+	Height int
+	// Average interval in seconds, zero means PRTG's default
+	Average int64
+	// HideChannels lists the channel ids not drawn, e.g. -4 for the downtime channel
+	HideChannels []int64
+	Theme        ChartTheme
+	// FontSize defaults to 10
+	FontSize   int
+	HideLegend bool
+}
+
+func (opts *ChartOptions) validate() error {
+	if opts.Format != ChartPNG && opts.Format != ChartSVG {
+		return fmt.Errorf("Unknown chart format: %v", opts.Format)
+	}
+	if opts.Graph < ChartGraphCustom || opts.Graph > ChartGraph365Days {
+		return fmt.Errorf("Unknown chart graph: %v", opts.Graph)
+	}
+	if _, ok := chartBackgrounds[opts.Theme]; !ok {
+		return fmt.Errorf("Unknown chart theme: %v", opts.Theme)
+	}
+	if opts.Width < 0 || opts.Height < 0 || opts.Average < 0 || opts.FontSize < 0 {
+		return fmt.Errorf("Width, height, average, and font size should be more than or equals to zero")
+	}
+	return nil
+}
+
+// validateDateFormat makes sure that the date is representable in PRTG's date format.
+func validateDateFormat(date time.Time) error {
+	if date.IsZero() {
+		return fmt.Errorf("Date should not be empty")
+	}
+	formatted := date.Format(dateFormat)
+	parsed, err := time.ParseInLocation(dateFormat, formatted, date.Location())
+	if err != nil || !parsed.Equal(date.Truncate(time.Second)) {
+		return fmt.Errorf("Date %v can't be formatted as %v", date, dateFormat)
+	}
+	return nil
+}
+
+// GetChart returns the sensor's chart image and its content type, e.g. "image/png".
+// Take start and end of date's boundaries, which are used by ChartGraphCustom only.
+func (c *Client) GetChart(id int64, startDate, endDate time.Time, opts *ChartOptions) ([]byte, string, error) {
+	if opts == nil {
+		opts = new(ChartOptions)
+	}
+	// Validate Input
+	if id < 0 {
+		return nil, "", fmt.Errorf("Id should be more than or equals to zero")
+	}
+	if err := opts.validate(); err != nil {
+		return nil, "", err
+	}
+	if opts.Graph == ChartGraphCustom {
+		for _, date := range []time.Time{startDate, endDate} {
+			if err := validateDateFormat(date); err != nil {
+				return nil, "", err
+			}
+		}
+		if !startDate.Before(endDate) {
+			return nil, "", fmt.Errorf("Start date should be before end date")
+		}
+	}
+
+	// Compose queries
+	q := c.getTemplateUrlQuery()
+	q.Set("type", "graph")
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("graphid", fmt.Sprintf("%v", int(opts.Graph)-1))
+	if opts.Graph == ChartGraphCustom {
+		q.Set("sdate", startDate.Format(dateFormat))
+		q.Set("edate", endDate.Format(dateFormat))
+	}
+	width, height, fontSize := opts.Width, opts.Height, opts.FontSize
+	if width == 0 {
+		width = defaultChartWidth
+	}
+	if height == 0 {
+		height = defaultChartHeight
+	}
+	if fontSize == 0 {
+		fontSize = defaultChartFontSize
+	}
+	q.Set("width", fmt.Sprintf("%v", width))
+	q.Set("height", fmt.Sprintf("%v", height))
+	if opts.Average > 0 {
+		q.Set("avg", fmt.Sprintf("%v", opts.Average))
+	}
+	if len(opts.HideChannels) > 0 {
+		hide := make([]string, len(opts.HideChannels))
+		for i, channel := range opts.HideChannels {
+			hide[i] = strconv.FormatInt(channel, 10)
+		}
+		q.Set("hide", strings.Join(hide, ","))
+	}
+	q.Set("bgcolor", chartBackgrounds[opts.Theme])
+	showLegend := 1
+	if opts.HideLegend {
+		showLegend = 0
+	}
+	q.Set("graphstyling", fmt.Sprintf("baseFontSize='%v' showLegend='%v'", fontSize, showLegend))
+
+	p := GetChartEndpointPNG
+	if opts.Format == ChartSVG {
+		p = GetChartEndpointSVG
+	}
+	body, _, err := c.getResponseBody(p, q)
+	if err != nil {
+		return nil, "", fmt.Errorf("Unable to get chart: %v", err)
+	}
+	contentType, ok := chartContentType(opts.Format, body)
+	if !ok {
+		return nil, "", fmt.Errorf("Unable to get chart: Response's content is not an image")
+	}
+	return body, contentType, nil
+}
+
+// chartContentType returns the content type of the chart,
+// and false if PRTG responds with something else, e.g. an error page.
+func chartContentType(format ChartFormat, body []byte) (string, bool) {
+	if format == ChartSVG {
+		// SVG may be served as XML
+		if sniffContent(body) == "xml" && bytes.Contains(body, []byte("<svg")) {
+			return "image/svg+xml", true
+		}
+		return "", false
+	}
+	contentType := http.DetectContentType(body)
+	return contentType, contentType == "image/png"
+}
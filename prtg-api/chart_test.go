@@ -0,0 +1,108 @@
+package prtg
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetChart(t *testing.T) {
+	var pngImage bytes.Buffer
+	png.Encode(&pngImage, image.NewRGBA(image.Rect(0, 0, 1, 1)))
+	var lastQuery map[string]string
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetChartEndpointPNG, func(w http.ResponseWriter, r *http.Request) {
+		lastQuery = map[string]string{}
+		for k := range r.URL.Query() {
+			lastQuery[k] = r.FormValue(k)
+		}
+		if r.FormValue("id") == "9000" {
+			responsesXmlOk(w, "/prtg_version_error.json")
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.WriteHeader(http.StatusOK)
+		w.Write(pngImage.Bytes())
+	})
+	mux.HandleFunc(GetChartEndpointSVG, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_chart_9321.svg"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	end := time.Date(2019, time.December, 7, 12, 0, 0, 0, time.UTC)
+	start := end.Add(-24 * time.Hour)
+
+	// PNG with default options
+	image, contentType, err := client.GetChart(9321, start, end, nil)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	} else if contentType != "image/png" || !bytes.Equal(image, pngImage.Bytes()) {
+		t.Errorf("Unexpected chart of %v, %v bytes", contentType, len(image))
+	}
+	expectedQuery := map[string]string{"type": "graph", "id": "9321", "graphid": "-1", "sdate": "2019-12-06-12-00-00",
+		"edate": "2019-12-07-12-00-00", "width": "850", "height": "270", "bgcolor": "#ffffff",
+		"graphstyling": "baseFontSize='10' showLegend='1'"}
+	for k, v := range expectedQuery {
+		if lastQuery[k] != v {
+			t.Errorf("Query %v is %q instead of %q", k, lastQuery[k], v)
+		}
+	}
+
+	// Predefined graph ignores the dates
+	_, _, err = client.GetChart(9321, time.Time{}, time.Time{}, &ChartOptions{
+		Graph: ChartGraph30Days, Width: 400, Height: 200, Average: 3600, HideChannels: []int64{-4, 2},
+		Theme: ChartThemeDark, FontSize: 12, HideLegend: true,
+	})
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	expectedQuery = map[string]string{"graphid": "2", "width": "400", "height": "200", "avg": "3600",
+		"hide": "-4,2", "bgcolor": "#1e1e1e", "graphstyling": "baseFontSize='12' showLegend='0'", "sdate": ""}
+	for k, v := range expectedQuery {
+		if lastQuery[k] != v {
+			t.Errorf("Query %v is %q instead of %q", k, lastQuery[k], v)
+		}
+	}
+
+	// SVG
+	image, contentType, err = client.GetChart(9321, start, end, &ChartOptions{Format: ChartSVG})
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	} else if contentType != "image/svg+xml" || len(image) == 0 {
+		t.Errorf("Unexpected chart of %v, %v bytes", contentType, len(image))
+	}
+
+	// PRTG's error is not an image
+	if _, _, err := client.GetChart(9000, start, end, nil); err == nil {
+		t.Errorf("Since the response is not an image, an error should occur.")
+	}
+
+	// Invalid input
+	invalidInputs := []struct {
+		id         int64
+		start, end time.Time
+		opts       *ChartOptions
+	}{
+		{-1, start, end, nil},
+		{9321, end, start, nil},
+		{9321, time.Time{}, end, nil},
+		{9321, start.AddDate(10000, 0, 0), start.AddDate(10001, 0, 0), nil},
+		{9321, start, end, &ChartOptions{Width: -1}},
+		{9321, start, end, &ChartOptions{Format: 5}},
+		{9321, start, end, &ChartOptions{Graph: -1}},
+		{9321, start, end, &ChartOptions{Theme: 7}},
+	}
+	for _, input := range invalidInputs {
+		if _, _, err := client.GetChart(input.id, input.start, input.end, input.opts); err == nil {
+			t.Errorf("Since the input is invalid (%v, %v, %v, %+v), an error should occur.", input.id, input.start, input.end, input.opts)
+		}
+	}
+}
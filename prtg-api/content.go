@@ -0,0 +1,101 @@
+package prtg
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// isContentXML reports whether the response should be decoded as XML.
+// The body is sniffed first since PRTG may label XML as text/html or JSON as text/xml,
+// then the Content-Type header is used for the ambiguous body.
+func isContentXML(header http.Header, body []byte) bool {
+	switch sniffContent(body) {
+	case "xml":
+		return true
+	case "json":
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	// PRTG serves some XML responses as text/html
+	return mediaType == "text/xml" || mediaType == "application/xml" ||
+		mediaType == "text/html" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// sniffContent returns "xml" or "json" based on the first meaningful character of the body,
+// or empty string if it's unknown.
+func sniffContent(body []byte) string {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(body, utf8BOM), " \t\r\n")
+	if len(trimmed) == 0 {
+		return ""
+	}
+	switch trimmed[0] {
+	case '<':
+		return "xml"
+	case '{', '[':
+		return "json"
+	}
+	return ""
+}
+
+// sanitizePrtgJSON fixes PRTG's known JSON quirks:
+// trailing commas before closing braces or brackets,
+// and unescaped control characters within strings.
+func sanitizePrtgJSON(body []byte) []byte {
+	var buf bytes.Buffer
+	buf.Grow(len(body))
+	inString := false
+	escaped := false
+	for i := 0; i < len(body); i++ {
+		ch := body[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			case ch < 0x20:
+				buf.WriteString(escapeControlCharacter(ch))
+				continue
+			}
+			buf.WriteByte(ch)
+			continue
+		}
+		switch ch {
+		case '"':
+			inString = true
+		case ',':
+			if isTrailingComma(body[i+1:]) {
+				continue
+			}
+		}
+		buf.WriteByte(ch)
+	}
+	return buf.Bytes()
+}
+
+func isTrailingComma(rest []byte) bool {
+	rest = bytes.TrimLeft(rest, " \t\r\n")
+	return len(rest) > 0 && (rest[0] == '}' || rest[0] == ']')
+}
+
+func escapeControlCharacter(ch byte) string {
+	switch ch {
+	case '\n':
+		return `\n`
+	case '\r':
+		return `\r`
+	case '\t':
+		return `\t`
+	}
+	return fmt.Sprintf(`\u%04x`, ch)
+}
@@ -0,0 +1,105 @@
+package prtg
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIsContentXML(t *testing.T) {
+	cases := []struct {
+		contentType string
+		body        string
+		isXML       bool
+	}{
+		{"text/xml; charset=UTF-8", "<prtg></prtg>", true},
+		{"text/xml; charset=utf-8", "", true},
+		{"text/xml", "", true},
+		{"application/xml", "", true},
+		{"application/atom+xml", "", true},
+		{"TEXT/HTML; charset=UTF-8", "", true},
+		{"application/json", "", false},
+		{"invalid;;", "", false},
+		{"", "", false},
+		// Headers lie, body wins
+		{"application/json", "\xEF\xBB\xBF  <?xml version=\"1.0\"?><prtg></prtg>", true},
+		{"text/html; charset=UTF-8", "\n {\"prtgversion\": \"18.2.41.1636\"}", false},
+		{"text/xml", "[]", false},
+	}
+	for _, c := range cases {
+		header := http.Header{}
+		header.Set("Content-Type", c.contentType)
+		if isXML := isContentXML(header, []byte(c.body)); isXML != c.isXML {
+			t.Errorf("Content %q with body %q should be xml: %v, but %v", c.contentType, c.body, c.isXML, isXML)
+		}
+	}
+}
+
+func TestSanitizePrtgJSON(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{`{"a": 1,}`, `{"a": 1}`},
+		{"{\"a\": [1, 2 ,\n ], }", "{\"a\": [1, 2 \n ] }"},
+		{`{"a": "x,}"}`, `{"a": "x,}"}`},
+		{"{\"a\": \"line1\nline2\ttab\x01\"}", `{"a": "line1\nline2\ttab\u0001"}`},
+		{`{"a": "quote \", ]"}`, `{"a": "quote \", ]"}`},
+	}
+	for _, c := range cases {
+		output := string(sanitizePrtgJSON([]byte(c.input)))
+		if output != c.expected {
+			t.Errorf("Sanitized %q is %q instead of %q", c.input, output, c.expected)
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(output), &v); err != nil {
+			t.Errorf("Sanitized %q should be valid JSON, but error: %v", c.input, err)
+		}
+	}
+}
+
+func TestGetSensorDetailMalformedJSON(t *testing.T) {
+	var xmlHits int64
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetSensorDetailsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		// Older PRTG labels JSON as text/html
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_sensor_9321_malformed.json"))
+	})
+	mux.HandleFunc(GetSensorDetailsEndpointXML, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&xmlHits, 1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	client := NewClient(fmt.Sprintf("%v", serverURL), "user", "pass")
+	sensorDetail, err := client.GetSensorDetail(9321)
+	if err != nil {
+		t.Errorf("Unable to get PRTG's Sensor Detail: %v", err)
+		return
+	}
+	if sensorDetail.Name != "Ping" {
+		t.Errorf("Sensor's name %v instead of Ping", sensorDetail.Name)
+	}
+	if sensorDetail.LastMessage != "Request timed out\tRetrying\nTimeout (ICMP error # 11010)" {
+		t.Errorf("Sensor's last message is %q", sensorDetail.LastMessage)
+	}
+	if xmlHits != 0 {
+		t.Errorf("XML endpoint should not be requested, but %v hit(s)", xmlHits)
+	}
+
+	// Known broken fixture should still fail
+	var resp prtgSensorDetailsResponse
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	err = decodePrtgResponse([]byte(loadfixture("/prtg_version_error.json")), &header, &resp)
+	if err == nil {
+		t.Errorf("It should be error since prtgversion is not a string")
+	}
+}
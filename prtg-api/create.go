@@ -0,0 +1,316 @@
+package prtg
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiscoveryType is the auto-discovery of a new device.
+type DiscoveryType int
+
+// Discovery types, with PRTG's value
+const (
+	// DiscoveryManual doesn't create any sensor
+	DiscoveryManual DiscoveryType = 0
+	// DiscoveryDefault creates the sensors of PRTG's default templates
+	DiscoveryDefault DiscoveryType = 1
+	// DiscoveryTemplates creates the sensors of DeviceOptions.Templates
+	DiscoveryTemplates DiscoveryType = 2
+)
+
+// DeviceOptions customizes the device added by AddDevice, nil means the default values.
+type DeviceOptions struct {
+	Discovery DiscoveryType
+	// Templates are the device template's files, e.g. "ping.odt",
+	// used when Discovery is DiscoveryTemplates
+	Templates []string
+	// IPv6 if the host is an IPv6 address or resolved as IPv6
+	IPv6 bool
+	Tags []string
+}
+
+const (
+	// addSensorPollInterval is the default interval of polling the sensor creation's progress
+	addSensorPollInterval = 500 * time.Millisecond
+	// addSensorPollLimit is the number of polls before giving up
+	addSensorPollLimit = 120
+)
+
+// maxSensorRows is the number of rows requested when listing a device's sensors,
+// since PRTG's table returns only 500 rows by default.
+const maxSensorRows = 50000
+
+// requestRedirect calls PRTG's API which redirects to the created object,
+// and returns where it redirects to. The cached responses are dropped afterward.
+func (c *Client) requestRedirect(p string, q *url.Values) (*url.URL, error) {
+	u, err := c.getCompleteUrl(p, q)
+	if err != nil {
+		return nil, err
+	}
+	_, header, err := c.sendHTTPRequest(p, q, u, false)
+	if err != nil {
+		return nil, err
+	}
+	c.InvalidateCache()
+	location, err := url.Parse(header.Get("Location"))
+	if err != nil || location.String() == "" {
+		return nil, fmt.Errorf("Response doesn't redirect to the created object")
+	}
+	return location, nil
+}
+
+// redirectedID returns the id query of the redirect's location, e.g. /device.htm?id=2050
+func redirectedID(location *url.URL) (int64, error) {
+	id, err := strconv.ParseInt(location.Query().Get("id"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Response doesn't redirect to the created object: %v", location.Path)
+	}
+	return id, nil
+}
+
+// AddGroup creates a group within the group or probe, and returns the new group's id.
+func (c *Client) AddGroup(parentID int64, name string) (int64, error) {
+	// Validate input
+	// Make sure that id is not less than 0
+	if parentID < 0 {
+		return 0, fmt.Errorf("Id should be more than or equals to zero")
+	}
+	if strings.TrimSpace(name) == "" {
+		return 0, fmt.Errorf("Name should not be empty")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", parentID))
+	q.Set("name_", name)
+	location, err := c.requestRedirect(AddGroupEndpoint, q)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to add group: %v", err)
+	}
+	id, err := redirectedID(location)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to add group: %v", err)
+	}
+	return id, nil
+}
+
+// AddDevice creates a device within the group or probe, and returns the new device's id.
+// The auto-discovery, if any, runs in background afterward.
+func (c *Client) AddDevice(parentGroupID int64, name, host string, opts *DeviceOptions) (int64, error) {
+	if opts == nil {
+		opts = new(DeviceOptions)
+	}
+	// Validate input
+	// Make sure that id is not less than 0
+	if parentGroupID < 0 {
+		return 0, fmt.Errorf("Id should be more than or equals to zero")
+	}
+	if strings.TrimSpace(name) == "" || strings.TrimSpace(host) == "" {
+		return 0, fmt.Errorf("Name and host should not be empty")
+	}
+	switch opts.Discovery {
+	case DiscoveryManual, DiscoveryDefault:
+	case DiscoveryTemplates:
+		if len(opts.Templates) == 0 {
+			return 0, fmt.Errorf("Templates should not be empty for the discovery with templates")
+		}
+	default:
+		return 0, fmt.Errorf("Unknown discovery type: %v", opts.Discovery)
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", parentGroupID))
+	q.Set("name_", name)
+	q.Set("host_", host)
+	ipVersion := 0
+	if opts.IPv6 {
+		ipVersion = 1
+	}
+	q.Set("ipversion_", fmt.Sprintf("%v", ipVersion))
+	q.Set("discoverytype_", fmt.Sprintf("%v", int(opts.Discovery)))
+	if opts.Discovery == DiscoveryTemplates {
+		q.Set("devicetemplate_", "1")
+		for _, template := range opts.Templates {
+			q.Add("devicetemplate__check", template)
+		}
+	}
+	if len(opts.Tags) > 0 {
+		q.Set("tags_", strings.Join(opts.Tags, " "))
+	}
+	location, err := c.requestRedirect(AddDeviceEndpoint, q)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to add device: %v", err)
+	}
+	id, err := redirectedID(location)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to add device: %v", err)
+	}
+	return id, nil
+}
+
+type prtgAddSensorProgress struct {
+	Progress  int64  `json:"progress"`
+	TargetURL string `json:"targeturl"`
+	Error     string `json:"error"`
+}
+
+// sensorIDs returns the ids of the device's sensors named name, or of all its sensors if name is empty.
+func (c *Client) sensorIDs(deviceID int64, name string) (map[int64]bool, error) {
+	filters := url.Values{}
+	filters.Set("count", fmt.Sprintf("%v", maxSensorRows))
+	sensorListResp, err := c.getFilteredTableList(deviceID, "sensors", []string{"objid", "name"}, filters)
+	if err != nil {
+		return nil, err
+	}
+	ids := map[int64]bool{}
+	for _, sensor := range sensorListResp.Sensors {
+		if name == "" || trimWeirdCharacter(sensor.Name) == name {
+			ids[sensor.ObjectId] = true
+		}
+	}
+	return ids, nil
+}
+
+// AddSensor creates sensors of the type on the device, and returns the new sensors' ids.
+// The params are the fields of PRTG's add sensor form, e.g. "name_" or "interval_",
+// and PRTG's default values are used for the missing ones.
+// PRTG may create more than one sensor, e.g. one per selected disk.
+// PRTG redirects to the new sensor when it creates only one. Otherwise the new sensors are found
+// by comparing the device's sensors named by the "name_" param, so a sensor added concurrently
+// by someone else is reported too if it has the same name.
+func (c *Client) AddSensor(deviceID int64, sensorType string, params map[string]string) ([]int64, error) {
+	// Validate input
+	// Make sure that id is not less than 0
+	if deviceID < 0 {
+		return nil, fmt.Errorf("Id should be more than or equals to zero")
+	}
+	if strings.TrimSpace(sensorType) == "" {
+		return nil, fmt.Errorf("Sensor type should not be empty")
+	}
+
+	// PRTG prepares the sensor's form in background, identified by tmpid
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", deviceID))
+	q.Set("sensortype", sensorType)
+	location, err := c.requestRedirect(AddSensorStartEndpoint, q)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to add sensor: %v", err)
+	}
+	tmpID := location.Query().Get("tmpid")
+	if tmpID == "" {
+		return nil, fmt.Errorf("Unable to add sensor: Response doesn't contain tmpid")
+	}
+	if err := c.waitAddSensorProgress(deviceID, tmpID); err != nil {
+		return nil, fmt.Errorf("Unable to add sensor: %v", err)
+	}
+
+	name := strings.TrimSpace(params["name_"])
+	before, err := c.sensorIDs(deviceID, name)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to add sensor: %v", err)
+	}
+	q = c.getTemplateUrlQuery()
+	for key, value := range params {
+		q.Set(key, value)
+	}
+	q.Set("id", fmt.Sprintf("%v", deviceID))
+	q.Set("sensortype", sensorType)
+	q.Set("tmpid", tmpID)
+	location, err = c.requestRedirect(AddSensorEndpoint, q)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to add sensor: %v", err)
+	}
+	if path.Base(location.Path) == "sensor.htm" {
+		id, err := redirectedID(location)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to add sensor: %v", err)
+		}
+		return []int64{id}, nil
+	}
+
+	// The new sensors are found by comparing the device's sensors
+	after, err := c.sensorIDs(deviceID, name)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to add sensor: %v", err)
+	}
+	ids := []int64{}
+	for id := range after {
+		if !before[id] {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("Unable to add sensor: No sensor is created")
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// waitAddSensorProgress polls the progress of preparing the sensor's form until it's done,
+// or the client's context is done.
+func (c *Client) waitAddSensorProgress(deviceID int64, tmpID string) error {
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", deviceID))
+	q.Set("tmpid", tmpID)
+	interval := c.pollInterval
+	if interval <= 0 {
+		interval = addSensorPollInterval
+	}
+	ctx := c.context()
+	for i := 0; i < addSensorPollLimit; i++ {
+		u, err := c.getCompleteUrl(AddSensorProgressEndpoint, q)
+		if err != nil {
+			return err
+		}
+		// The progress should never be cached
+		body, header, err := c.getHTTPBody(AddSensorProgressEndpoint, q, u)
+		if err != nil {
+			return err
+		}
+		var progress prtgAddSensorProgress
+		if err := decodePrtgResponse(body, header, &progress); err != nil {
+			return err
+		}
+		if progress.Error != "" {
+			return fmt.Errorf("%v", trimWeirdCharacter(progress.Error))
+		}
+		if progress.Progress < 0 {
+			return fmt.Errorf("Sensor's preparation failed")
+		}
+		if progress.Progress >= 100 {
+			return nil
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return fmt.Errorf("Sensor's preparation doesn't finish after %v polls", addSensorPollLimit)
+}
+
+// RunAutoDiscovery starts the auto-discovery of the device, or of every device within the group or probe.
+// The templates are the device template's files, e.g. "ping.odt", or nil for the device's own templates.
+func (c *Client) RunAutoDiscovery(id int64, templates []string) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return fmt.Errorf("Id should be more than or equals to zero")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	if len(templates) > 0 {
+		q.Set("template", strings.Join(templates, ","))
+	}
+	if err := c.requestAction(AutoDiscoveryEndpoint, q); err != nil {
+		return fmt.Errorf("Unable to run auto-discovery: %v", err)
+	}
+	return nil
+}
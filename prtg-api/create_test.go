@@ -0,0 +1,230 @@
+package prtg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddGroupAndDevice(t *testing.T) {
+	var query map[string][]string
+	mux := new(http.ServeMux)
+	for path, page := range map[string]string{AddGroupEndpoint: "group", AddDeviceEndpoint: "device"} {
+		page := page
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query()
+			if r.FormValue("id") == "9000" {
+				responsesXmlOk(w, "/prtg_version_error.json")
+				return
+			}
+			http.Redirect(w, r, fmt.Sprintf("/%v.htm?id=2050", page), http.StatusFound)
+		})
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("The redirect to %v should not be followed", r.URL.Path)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	id, err := client.AddGroup(1, "Branch")
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	} else if id != 2050 {
+		t.Errorf("Group's id is %v instead of 2050", id)
+	}
+	if query["name_"][0] != "Branch" || query["id"][0] != "1" {
+		t.Errorf("Unexpected query: %v", query)
+	}
+
+	id, err = client.AddDevice(2050, "Router", "10.0.0.1", &DeviceOptions{
+		Discovery: DiscoveryTemplates,
+		Templates: []string{"ping.odt", "snmp.odt"},
+		Tags:      []string{"cisco", "branch"},
+	})
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	} else if id != 2050 {
+		t.Errorf("Device's id is %v instead of 2050", id)
+	}
+	expected := map[string][]string{
+		"name_": {"Router"}, "host_": {"10.0.0.1"}, "ipversion_": {"0"}, "discoverytype_": {"2"},
+		"devicetemplate_": {"1"}, "devicetemplate__check": {"ping.odt", "snmp.odt"}, "tags_": {"cisco branch"},
+	}
+	for key, values := range expected {
+		if !reflect.DeepEqual(query[key], values) {
+			t.Errorf("Query %v is %v instead of %v", key, query[key], values)
+		}
+	}
+	if _, err := client.AddDevice(2050, "Router", "10.0.0.1", nil); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if _, ok := query["devicetemplate_"]; ok || query["discoverytype_"][0] != "0" {
+		t.Errorf("Unexpected query: %v", query)
+	}
+
+	// PRTG's error
+	if _, err := client.AddGroup(9000, "Branch"); err == nil {
+		t.Errorf("Since the response doesn't redirect, an error should occur.")
+	}
+	if _, err := client.AddDevice(9000, "Router", "10.0.0.1", nil); err == nil {
+		t.Errorf("Since the response doesn't redirect, an error should occur.")
+	}
+
+	// Invalid input
+	if _, err := client.AddGroup(-1, "Branch"); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+	if _, err := client.AddGroup(1, " "); err == nil {
+		t.Errorf("Since the name is empty, an error should occur.")
+	}
+	if _, err := client.AddDevice(1, "Router", "", nil); err == nil {
+		t.Errorf("Since the host is empty, an error should occur.")
+	}
+	if _, err := client.AddDevice(1, "Router", "10.0.0.1", &DeviceOptions{Discovery: DiscoveryTemplates}); err == nil {
+		t.Errorf("Since the templates are empty, an error should occur.")
+	}
+	if _, err := client.AddDevice(1, "Router", "10.0.0.1", &DeviceOptions{Discovery: 9}); err == nil {
+		t.Errorf("Since the discovery type is unknown, an error should occur.")
+	}
+}
+
+func TestAddSensor(t *testing.T) {
+	progress := []int{0, 40, 100}
+	polls := 0
+	sensors := map[int64]string{2100: "Disk"}
+	var query map[string][]string
+	var count string
+	mux := new(http.ServeMux)
+	mux.HandleFunc(AddSensorStartEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/addsensor4.htm?id="+r.FormValue("id")+"&tmpid=7", http.StatusFound)
+	})
+	mux.HandleFunc(AddSensorProgressEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.FormValue("tmpid") != "7" {
+			fmt.Fprint(w, `{"progress":-1,"error":"Unknown tmpid"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"progress":%v}`, progress[polls%len(progress)])
+		polls++
+	})
+	mux.HandleFunc(AddSensorEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		switch r.FormValue("sensortype") {
+		case "ping":
+			// A single sensor is redirected to
+			http.Redirect(w, r, "/sensor.htm?id=2110", http.StatusFound)
+			return
+		case "diskspace":
+			// Another sensor is added to the device meanwhile
+			sensors[2101], sensors[2102], sensors[2103] = "Disk", "Disk", "HTTP"
+		}
+		http.Redirect(w, r, "/device.htm?id="+r.FormValue("id"), http.StatusFound)
+	})
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		count = r.FormValue("count")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"prtg-version":"18.2.41.1652","treesize":0,"sensors":[`)
+		i := 0
+		for id, name := range sensors {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"objid":%v,"name":%q}`, id, name)
+			i++
+		}
+		fmt.Fprint(w, `]}`)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+	client.pollInterval = time.Millisecond
+
+	ids, err := client.AddSensor(2050, "diskspace", map[string]string{"name_": "Disk", "interval_": "300|5 minutes"})
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if !reflect.DeepEqual(ids, []int64{2101, 2102}) {
+		t.Errorf("New sensors are %v instead of [2101 2102]", ids)
+	}
+	if polls != 3 {
+		t.Errorf("Progress should be polled 3 times instead of %v", polls)
+	}
+	if query["tmpid"][0] != "7" || query["id"][0] != "2050" || query["sensortype"][0] != "diskspace" ||
+		query["name_"][0] != "Disk" || query["interval_"][0] != "300|5 minutes" {
+		t.Errorf("Unexpected query: %v", query)
+	}
+	if count != "50000" {
+		t.Errorf("The sensors should be listed with count 50000 instead of %q", count)
+	}
+
+	// The id of the single sensor is taken from the redirect
+	polls = 2
+	ids, err = client.AddSensor(2050, "ping", nil)
+	if err != nil || !reflect.DeepEqual(ids, []int64{2110}) {
+		t.Errorf("New sensor is %v instead of [2110], error: %v", ids, err)
+	}
+
+	// No sensor is created
+	polls = 2
+	if _, err := client.AddSensor(2050, "nodata", nil); err == nil {
+		t.Errorf("Since no sensor is created, an error should occur.")
+	}
+
+	// The polling stops once the client's context is cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := client.WithContext(ctx)
+	cancelled.pollInterval = time.Hour
+	polls = 0
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := cancelled.AddSensor(2050, "ping", nil); err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Errorf("Since the context is cancelled, an error should occur instead of %v", err)
+	}
+	if polls != 1 {
+		t.Errorf("Progress should be polled once instead of %v times", polls)
+	}
+
+	// Invalid input
+	if _, err := client.AddSensor(-1, "ping", nil); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+	if _, err := client.AddSensor(2050, "", nil); err == nil {
+		t.Errorf("Since the sensor type is empty, an error should occur.")
+	}
+}
+
+func TestRunAutoDiscovery(t *testing.T) {
+	var query map[string]string
+	mux := new(http.ServeMux)
+	mux.HandleFunc(AutoDiscoveryEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{"id": r.FormValue("id"), "template": r.FormValue("template")}
+		if r.FormValue("id") == "9000" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	if err := client.RunAutoDiscovery(2050, []string{"ping.odt", "snmp.odt"}); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if query["id"] != "2050" || query["template"] != "ping.odt,snmp.odt" {
+		t.Errorf("Unexpected query: %v", query)
+	}
+	if err := client.RunAutoDiscovery(9000, nil); err == nil {
+		t.Errorf("Since the object doesn't exist, an error should occur.")
+	}
+	if err := client.RunAutoDiscovery(-1, nil); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+}
@@ -0,0 +1,168 @@
+package prtg
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// prtgHistoricDateFormat is the layout of historic data's datetime, e.g. "6/1/2018 12:04:36 AM"
+	prtgHistoricDateFormat = "1/2/2006 3:04:05 PM"
+	// rawSuffix is appended by PRTG to the channel's name for its raw value
+	rawSuffix = "(RAW)"
+)
+
+// HistoricDataCSVOptions configures HistoricDataCSVWriter.
+type HistoricDataCSVOptions struct {
+	// Channels lists the channel columns written after datetime and coverage.
+	// If it's nil, the channels found in the first written data are used, sorted by name.
+	Channels []string
+
+	// Raw writes the raw value of each channel and coverage instead of the formatted one, if available.
+	Raw bool
+
+	// TimeFormat is the layout of datetime column.
+	// If it's empty, the datetime is written as returned by PRTG.
+	TimeFormat string
+
+	// Location is the timezone of datetime column, it's only used with TimeFormat.
+	// PRTG's datetime is read as UTC. The default value is UTC.
+	Location *time.Location
+}
+
+// HistoricDataCSVWriter writes the result of GetHistoricData as RFC 4180 CSV.
+// The header is written along with the first data,
+// so the series could be streamed by calling Write multiple times.
+type HistoricDataCSVWriter struct {
+	w       *csv.Writer
+	opts    HistoricDataCSVOptions
+	columns []string
+}
+
+// NewHistoricDataCSVWriter takes the destination and options, and returns the writer's instance.
+// The default options are used if opts is nil.
+func NewHistoricDataCSVWriter(w io.Writer, opts *HistoricDataCSVOptions) *HistoricDataCSVWriter {
+	instance := new(HistoricDataCSVWriter)
+	instance.w = csv.NewWriter(w)
+	instance.w.UseCRLF = true
+	if opts != nil {
+		instance.opts = *opts
+	}
+	if instance.opts.Location == nil {
+		instance.opts.Location = time.UTC
+	}
+	return instance
+}
+
+// Write writes the header if it hasn't been written, then a record for each data.
+// Channels which aren't part of the header are skipped.
+func (cw *HistoricDataCSVWriter) Write(histData []PrtgHistoricData) error {
+	if cw.columns == nil {
+		cw.columns = cw.opts.Channels
+		if cw.columns == nil {
+			cw.columns = HistoricDataChannels(histData)
+		}
+		header := append([]string{"datetime", "coverage"}, cw.columns...)
+		if err := cw.w.Write(header); err != nil {
+			return fmt.Errorf("Unable to write csv header: %v", err)
+		}
+	}
+	for _, data := range histData {
+		record, err := cw.record(data)
+		if err != nil {
+			return err
+		}
+		if err := cw.w.Write(record); err != nil {
+			return fmt.Errorf("Unable to write csv record: %v", err)
+		}
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *HistoricDataCSVWriter) record(data PrtgHistoricData) ([]string, error) {
+	record := make([]string, 0, len(cw.columns)+2)
+	datetime, err := cw.datetime(data)
+	if err != nil {
+		return nil, err
+	}
+	record = append(record, datetime)
+	if cw.opts.Raw {
+		record = append(record, cw.value(data, "coverage_raw", "coverage"))
+	} else {
+		record = append(record, cw.value(data, "coverage"))
+	}
+	for _, channel := range cw.columns {
+		if cw.opts.Raw {
+			record = append(record, cw.value(data, channel+rawSuffix, channel))
+		} else {
+			record = append(record, cw.value(data, channel))
+		}
+	}
+	return record, nil
+}
+
+// value returns the first existing key's value as string.
+func (cw *HistoricDataCSVWriter) value(data PrtgHistoricData, keys ...string) string {
+	for _, key := range keys {
+		if val, ok := data[key]; ok {
+			return formatHistoricValue(val)
+		}
+	}
+	return ""
+}
+
+func (cw *HistoricDataCSVWriter) datetime(data PrtgHistoricData) (string, error) {
+	formatted := cw.value(data, "datetime")
+	if cw.opts.TimeFormat == "" {
+		return formatted, nil
+	}
+	t, err := data.Time()
+	if err != nil {
+		return "", err
+	}
+	return t.In(cw.opts.Location).Format(cw.opts.TimeFormat), nil
+}
+
+// HistoricDataChannels returns the sorted channel's names of the data, excluding raw values.
+func HistoricDataChannels(histData []PrtgHistoricData) []string {
+	found := map[string]bool{}
+	for _, data := range histData {
+		for key := range data {
+			switch key {
+			case "datetime", "datetime_raw", "coverage", "coverage_raw":
+				continue
+			}
+			found[strings.TrimSuffix(key, rawSuffix)] = true
+		}
+	}
+	channels := make([]string, 0, len(found))
+	for channel := range found {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+func formatHistoricValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return trimWeirdCharacter(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+// WriteHistoricDataCSV writes the historic data as RFC 4180 CSV.
+// The default options are used if opts is nil.
+func WriteHistoricDataCSV(w io.Writer, histData []PrtgHistoricData, opts *HistoricDataCSVOptions) error {
+	return NewHistoricDataCSVWriter(w, opts).Write(histData)
+}
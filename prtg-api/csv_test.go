@@ -0,0 +1,129 @@
+package prtg
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestWriteHistoricDataCSV(t *testing.T) {
+	histData := []PrtgHistoricData{
+		{
+			"datetime":          "6/1/2018 12:04:36 AM",
+			"datetime_raw":      43252.0031944444,
+			"Loading time":      "172 msec",
+			"Loading time(RAW)": float64(172),
+			"Bytes received":    float64(26097),
+			"coverage":          "100 %",
+			"coverage_raw":      float64(10000),
+		},
+		{
+			"datetime":     "6/1/2018 12:09:36 AM - 12:14:36 AM",
+			"Loading time": "1,188 msec",
+			"coverage":     "100 %",
+		},
+	}
+
+	// Default options
+	var buf bytes.Buffer
+	err := WriteHistoricDataCSV(&buf, histData, nil)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	expected := "datetime,coverage,Bytes received,Loading time\r\n" +
+		"6/1/2018 12:04:36 AM,100 %,26097,172 msec\r\n" +
+		"6/1/2018 12:09:36 AM - 12:14:36 AM,100 %,,\"1,188 msec\"\r\n"
+	if buf.String() != expected {
+		t.Errorf("CSV is %q instead of %q", buf.String(), expected)
+	}
+
+	// Raw value, ordered channels, and custom time format
+	buf.Reset()
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	err = WriteHistoricDataCSV(&buf, histData, &HistoricDataCSVOptions{
+		Channels:   []string{"Loading time"},
+		Raw:        true,
+		TimeFormat: time.RFC3339,
+		Location:   jakarta,
+	})
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	expected = "datetime,coverage,Loading time\r\n" +
+		"2018-06-01T07:04:36+07:00,10000,172\r\n" +
+		"2018-06-01T07:09:36+07:00,100 %,\"1,188 msec\"\r\n"
+	if buf.String() != expected {
+		t.Errorf("CSV is %q instead of %q", buf.String(), expected)
+	}
+
+	// Header is only written once
+	buf.Reset()
+	writer := NewHistoricDataCSVWriter(&buf, &HistoricDataCSVOptions{Channels: []string{"Bytes received"}})
+	for _, data := range histData {
+		if err := writer.Write([]PrtgHistoricData{data}); err != nil {
+			t.Errorf("It should be success but error: %v", err)
+			return
+		}
+	}
+	expected = "datetime,coverage,Bytes received\r\n" +
+		"6/1/2018 12:04:36 AM,100 %,26097\r\n" +
+		"6/1/2018 12:09:36 AM - 12:14:36 AM,100 %,\r\n"
+	if buf.String() != expected {
+		t.Errorf("CSV is %q instead of %q", buf.String(), expected)
+	}
+
+	// Invalid datetime
+	err = WriteHistoricDataCSV(&buf, []PrtgHistoricData{{"datetime": "yesterday"}},
+		&HistoricDataCSVOptions{TimeFormat: time.RFC3339})
+	if err == nil {
+		t.Errorf("It should be error since the datetime is invalid")
+	}
+}
+
+func TestGetHistoricDataCSV(t *testing.T) {
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetHistoricDatasEndpointCSV, func(w http.ResponseWriter, r *http.Request) {
+		sensorId := r.FormValue("id")
+		w.Header().Set("Content-Type", "text/csv; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		if sensorId == "9321" {
+			fmt.Fprint(w, loadfixture("/prtg_histdata_9321.csv"))
+		} else {
+			fmt.Fprint(w, loadfixture("/prtg_histdata_9321.xml"))
+		}
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	client := NewClient(fmt.Sprintf("%v", serverURL), "user", "pass")
+	sDate := time.Date(2019, time.December, 7, 0, 0, 0, 0, time.UTC)
+	eDate := time.Date(2019, time.December, 8, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	err := client.GetHistoricDataCSV(&buf, 9321, 0, sDate, eDate)
+	if err != nil {
+		t.Errorf("Unable to get PRTG's Historic Data: %v", err)
+		return
+	}
+	if buf.String() != loadfixture("/prtg_histdata_9321.csv") {
+		t.Errorf("CSV is %q instead of the fixture", buf.String())
+	}
+
+	// PRTG's error response
+	err = client.GetHistoricDataCSV(&buf, 9000, 0, sDate, eDate)
+	if err == nil {
+		t.Errorf("Since the response's body is XML, an error should occur.")
+	}
+
+	// Should return error, if data range is more than 31 days
+	err = client.GetHistoricDataCSV(&buf, 9321, 0, sDate, sDate.AddDate(0, 2, 0))
+	if err == nil {
+		t.Errorf("Since the date range is more than 31 days, an error should occur.")
+	}
+}
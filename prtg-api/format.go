@@ -0,0 +1,137 @@
+package prtg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ResponseFormat selects the format of PRTG's API response,
+// for the endpoints which are available both in JSON and XML.
+type ResponseFormat int
+
+const (
+	// FormatAuto tries JSON first and falls back to XML,
+	// then remembers the working format for the server's version.
+	FormatAuto ResponseFormat = iota
+	// FormatJSON only uses the JSON endpoints.
+	FormatJSON
+	// FormatXML only uses the XML endpoints.
+	FormatXML
+)
+
+func (f ResponseFormat) String() string {
+	switch f {
+	case FormatAuto:
+		return "auto"
+	case FormatJSON:
+		return "json"
+	case FormatXML:
+		return "xml"
+	}
+	return fmt.Sprintf("ResponseFormat(%d)", int(f))
+}
+
+// formatDetection contains the format detected on the client's server.
+// It's dropped once the server reports another version, since an upgrade may change the formats it serves.
+type formatDetection struct {
+	mu      sync.Mutex
+	version string
+	format  ResponseFormat
+}
+
+// SetFormat configures the response format used by the client.
+func (c *Client) SetFormat(format ResponseFormat) error {
+	if format < FormatAuto || format > FormatXML {
+		return fmt.Errorf("Unknown response format: %v", format)
+	}
+	c.Format = format
+	return nil
+}
+
+// DetectedFormat returns the format detected by FormatAuto and the PRTG's version it was detected on.
+// It returns FormatAuto if nothing has been detected yet.
+func (c *Client) DetectedFormat() (ResponseFormat, string) {
+	if c.detected == nil {
+		return FormatAuto, ""
+	}
+	c.detected.mu.Lock()
+	defer c.detected.mu.Unlock()
+	return c.detected.format, c.detected.version
+}
+
+// ResetDetectedFormat forgets the detected format of the client's server,
+// so the next request probes the server again.
+func (c *Client) ResetDetectedFormat() {
+	c.setDetectedFormat("", FormatAuto)
+}
+
+func (c *Client) setDetectedFormat(version string, format ResponseFormat) {
+	if c.detected == nil {
+		return
+	}
+	c.detected.mu.Lock()
+	defer c.detected.mu.Unlock()
+	c.detected.version, c.detected.format = version, format
+}
+
+// isFormatError reports whether the other format may succeed where this one failed.
+// Transport failures and HTTP status other than 404 are returned as is.
+func isFormatError(err error) bool {
+	var decodeErr *decodeError
+	if errors.As(err, &decodeErr) {
+		return true
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// requestWithFormat calls requestJSON and/or requestXML depending on the client's format.
+// Both request functions send the requests through the given client,
+// and return the PRTG's version found in the response.
+func (c *Client) requestWithFormat(requestJSON, requestXML func(*Client) (string, error)) error {
+	switch c.Format {
+	case FormatJSON:
+		_, err := requestJSON(c)
+		return err
+	case FormatXML:
+		_, err := requestXML(c)
+		return err
+	}
+
+	// Start from the detected format, if any
+	first, second := requestJSON, requestXML
+	firstFormat, secondFormat := FormatJSON, FormatXML
+	detected, detectedVersion := c.DetectedFormat()
+	if detected == FormatXML {
+		first, second = second, first
+		firstFormat, secondFormat = secondFormat, firstFormat
+	}
+
+	version, err := first(c)
+	if err == nil {
+		if detected == FormatXML && version != detectedVersion {
+			// The server has been upgraded, so the next request probes the preferred format again
+			c.ResetDetectedFormat()
+			return nil
+		}
+		c.setDetectedFormat(version, firstFormat)
+		return nil
+	}
+	if !isFormatError(err) {
+		return err
+	}
+	c.reportFormatFallback(firstFormat, secondFormat, err)
+	retry := *c
+	retry.retry++
+	version, secondErr := second(&retry)
+	if secondErr != nil {
+		return fmt.Errorf("%v response: %v | %v response: %v", firstFormat, err, secondFormat, secondErr)
+	}
+	c.setDetectedFormat(version, secondFormat)
+	return nil
+}
@@ -0,0 +1,207 @@
+package prtg
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSetFormat(t *testing.T) {
+	client := NewClient("http://localhost", "user", "pass")
+	if client.Format != FormatAuto {
+		t.Errorf("Default format is %v instead of auto", client.Format)
+	}
+	if err := client.SetFormat(FormatXML); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if client.Format != FormatXML {
+		t.Errorf("Format is %v instead of xml", client.Format)
+	}
+	if err := client.SetFormat(ResponseFormat(10)); err == nil {
+		t.Errorf("It should be error since the format is unknown")
+	}
+}
+
+func TestFormatUnauthorizedIsNotRetried(t *testing.T) {
+	var xmlHits int64
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetSensorDetailsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc(GetSensorDetailsEndpointXML, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&xmlHits, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	client := NewClient(fmt.Sprintf("%v", serverURL), "user", "wrong")
+	_, err := client.GetPrtgVersion()
+	if err == nil {
+		t.Errorf("It should be error since the credential is wrong")
+		return
+	}
+	if !strings.Contains(err.Error(), "Wrong Username and/or Password") {
+		t.Errorf("Error should report the wrong credential, but: %v", err)
+	}
+	if xmlHits != 0 {
+		t.Errorf("XML endpoint should not be requested, but %v hit(s)", xmlHits)
+	}
+}
+
+func TestFormatAutoDetection(t *testing.T) {
+	var jsonHits, xmlHits int64
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetSensorDetailsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&jsonHits, 1)
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc(GetSensorDetailsEndpointXML, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&xmlHits, 1)
+		w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_sensor-detail.xml"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	client := NewClient(fmt.Sprintf("%v", serverURL), "user", "pass")
+	if format, _ := client.DetectedFormat(); format != FormatAuto {
+		t.Errorf("Detected format is %v before any request instead of auto", format)
+	}
+
+	sensorDetail, err := client.GetSensorDetail(7888)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if sensorDetail.Name != "SNMP System Uptime" {
+		t.Errorf("Sensor's name %v instead of SNMP System Uptime", sensorDetail.Name)
+	}
+	format, version := client.DetectedFormat()
+	if format != FormatXML || version != "13.1.2.1462" {
+		t.Errorf("Detected format is %v on %v instead of xml on 13.1.2.1462", format, version)
+	}
+
+	// Next request should go to XML directly
+	_, err = client.GetSensorDetail(7888)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if jsonHits != 1 || xmlHits != 2 {
+		t.Errorf("JSON should be hit once and XML twice, but %v and %v hit(s)", jsonHits, xmlHits)
+	}
+
+	// Explicit JSON should not fall back
+	client.Format = FormatJSON
+	_, err = client.GetSensorDetail(7888)
+	if err == nil {
+		t.Errorf("It should be error since JSON endpoint is not found")
+	}
+	if xmlHits != 2 {
+		t.Errorf("XML endpoint should not be requested with explicit JSON format, but %v hit(s)", xmlHits)
+	}
+
+	// The detected format belongs to the client, not to the server
+	other := NewClient(fmt.Sprintf("%v", serverURL), "user", "pass")
+	if format, _ := other.DetectedFormat(); format != FormatAuto {
+		t.Errorf("Detected format of another client is %v instead of auto", format)
+	}
+	client.ResetDetectedFormat()
+	if format, version := client.DetectedFormat(); format != FormatAuto || version != "" {
+		t.Errorf("Detected format is %v on %v after reset instead of auto", format, version)
+	}
+}
+
+func TestFormatDetectionUpgrade(t *testing.T) {
+	var upgraded int32
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetSensorDetailsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&upgraded) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_sensor_9321.json"))
+	})
+	mux.HandleFunc(GetSensorDetailsEndpointXML, func(w http.ResponseWriter, r *http.Request) {
+		content := loadfixture("/prtg_sensor-detail.xml")
+		if atomic.LoadInt32(&upgraded) != 0 {
+			content = strings.Replace(content, "13.1.2.1462", "18.2.41.1636", 1)
+		}
+		w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, content)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	client := NewClient(fmt.Sprintf("%v", serverURL), "user", "pass")
+	if _, err := client.GetSensorDetail(7888); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if format, version := client.DetectedFormat(); format != FormatXML || version != "13.1.2.1462" {
+		t.Errorf("Detected format is %v on %v instead of xml on 13.1.2.1462", format, version)
+	}
+
+	// The detected format should be dropped once the server reports another version
+	atomic.StoreInt32(&upgraded, 1)
+	if _, err := client.GetSensorDetail(7888); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if format, _ := client.DetectedFormat(); format != FormatAuto {
+		t.Errorf("Detected format is %v after the upgrade instead of auto", format)
+	}
+
+	// Next request should probe JSON again
+	sensorDetail, err := client.GetSensorDetail(9321)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if sensorDetail.Name != "Ping" {
+		t.Errorf("Sensor's name %v instead of Ping", sensorDetail.Name)
+	}
+	if format, version := client.DetectedFormat(); format != FormatJSON || version != "18.2.41.1636" {
+		t.Errorf("Detected format is %v on %v instead of json on 18.2.41.1636", format, version)
+	}
+}
+
+func TestFormatBothErrorsSurfaced(t *testing.T) {
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetHistoricDatasEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "{")
+	})
+	mux.HandleFunc(GetHistoricDatasEndpointXML, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	client := NewClient(fmt.Sprintf("%v", serverURL), "user", "pass")
+	sensorId, average, sDate, eDate := composeDummyHistAPIParam()
+	_, err := client.GetHistoricData(sensorId, average, sDate, eDate)
+	if err == nil {
+		t.Errorf("It should be error since both formats fail")
+		return
+	}
+	if !strings.Contains(err.Error(), "json response: Unable to unmarshal json response") {
+		t.Errorf("Error should contain the JSON failure, but: %v", err)
+	}
+	if !strings.Contains(err.Error(), "xml response: HTTP Response status NOK: 500") {
+		t.Errorf("Error should contain the XML failure, but: %v", err)
+	}
+	if format, _ := client.DetectedFormat(); format != FormatAuto {
+		t.Errorf("Nothing should be detected when both formats fail, but %v", format)
+	}
+}
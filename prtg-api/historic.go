@@ -0,0 +1,68 @@
+package prtg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time returns the start of the record's interval in UTC.
+// The raw datetime is used if available, otherwise the formatted one is read as UTC.
+func (data PrtgHistoricData) Time() (time.Time, error) {
+	if raw := formatHistoricValue(data["datetime_raw"]); !isPrtgEmpty(raw) {
+		days, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Unable to parse datetime_raw %q: %v", raw, err)
+		}
+		return ConvertPrtgDateTime(days), nil
+	}
+	// Averaged data contains the period, e.g. "6/1/2018 12:00:00 AM - 12:05:00 AM"
+	formatted := formatHistoricValue(data["datetime"])
+	start := strings.SplitN(formatted, " - ", 2)[0]
+	t, err := time.ParseInLocation(prtgHistoricDateFormat, start, time.UTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Unable to parse datetime %q: %v", formatted, err)
+	}
+	return t, nil
+}
+
+// Coverage returns the record's coverage in percent.
+func (data PrtgHistoricData) Coverage() (float64, error) {
+	if raw := formatHistoricValue(data["coverage_raw"]); !isPrtgEmpty(raw) {
+		// The raw coverage is in hundredths of percent
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return 0, fmt.Errorf("Unable to parse coverage_raw %q: %v", raw, err)
+		}
+		return value / 100, nil
+	}
+	formatted := formatHistoricValue(data["coverage"])
+	value, err := parsePrtgPercent(formatted)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to parse coverage %q: %v", formatted, err)
+	}
+	return value, nil
+}
+
+// Value returns the channel's formatted value as number, e.g. 12 for "12 msec",
+// and false if the channel has no data in the record.
+func (data PrtgHistoricData) Value(channel string) (float64, bool) {
+	return parseHistoricValue(data[channel])
+}
+
+// RawValue returns the channel's raw value, and false if the record has no raw value of the channel.
+func (data PrtgHistoricData) RawValue(channel string) (float64, bool) {
+	return parseHistoricValue(data[channel+rawSuffix])
+}
+
+func parseHistoricValue(val interface{}) (float64, bool) {
+	fields := strings.Fields(formatHistoricValue(val))
+	if len(fields) == 0 || isPrtgEmpty(fields[0]) {
+		return 0, false
+	}
+	// Drop the thousands separator and the unit, e.g. "1,234 kbit/s" or "50%"
+	str := strings.TrimSuffix(strings.Replace(fields[0], ",", "", -1), "%")
+	value, err := strconv.ParseFloat(str, 64)
+	return value, err == nil
+}
@@ -0,0 +1,51 @@
+package prtg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrtgHistoricDataAccessors(t *testing.T) {
+	expected := time.Date(2018, time.June, 1, 0, 5, 0, 0, time.UTC)
+	data := []PrtgHistoricData{
+		// JSON with raw values
+		{"datetime": "6/1/2018 12:05:00 AM", "datetime_raw": 43252.00347222222, "coverage": "50 %", "coverage_raw": float64(5000),
+			"Traffic In": "1,234 kbit/s", "Traffic In(RAW)": float64(154250), "Downtime": ""},
+		// XML of averaged data
+		{"datetime": "6/1/2018 12:05:00 AM - 12:10:00 AM", "coverage": "50 %", "Traffic In": "1234.5000", "Downtime": "0 %"},
+	}
+	for i, d := range data {
+		if datetime, err := d.Time(); err != nil || !datetime.Equal(expected) {
+			t.Errorf("Datetime of %v is %v instead of %v, error: %v", i, datetime, expected, err)
+		}
+		if coverage, err := d.Coverage(); err != nil || coverage != 50 {
+			t.Errorf("Coverage of %v is %v instead of 50, error: %v", i, coverage, err)
+		}
+		if _, ok := d.Value("Ping Time"); ok {
+			t.Errorf("Record %v has no Ping Time", i)
+		}
+	}
+	if value, ok := data[0].Value("Traffic In"); !ok || value != 1234 {
+		t.Errorf("Traffic In is %v instead of 1234", value)
+	}
+	if value, ok := data[0].RawValue("Traffic In"); !ok || value != 154250 {
+		t.Errorf("Raw Traffic In is %v instead of 154250", value)
+	}
+	if _, ok := data[0].Value("Downtime"); ok {
+		t.Errorf("Empty Downtime should have no value")
+	}
+	if value, ok := data[1].Value("Downtime"); !ok || value != 0 {
+		t.Errorf("Downtime is %v instead of 0", value)
+	}
+	if _, ok := data[1].RawValue("Traffic In"); ok {
+		t.Errorf("Record has no raw value")
+	}
+
+	invalid := PrtgHistoricData{"datetime": "yesterday", "coverage": "all"}
+	if _, err := invalid.Time(); err == nil {
+		t.Errorf("Since the datetime is invalid, an error should occur.")
+	}
+	if _, err := invalid.Coverage(); err == nil {
+		t.Errorf("Since the coverage is invalid, an error should occur.")
+	}
+}
@@ -0,0 +1,163 @@
+package prtg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Logger receives the client's log records as a message followed by alternating keys and values,
+// in the style of log/slog. *slog.Logger satisfies it.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// RequestInfo describes an HTTP request sent to PRTG.
+// It never contains the credentials.
+type RequestInfo struct {
+	// ID is unique per request, to match OnRequest with OnResponse or OnError.
+	ID uint64
+	// Endpoint's path, e.g. "/api/table.json"
+	Endpoint string
+	// ObjectID is the requested object's id, or -1 if the request has none.
+	ObjectID int64
+	Start    time.Time
+	// Retry is the number of the call's preceding attempts,
+	// e.g. 1 when FormatAuto retries the request in the other format.
+	Retry int
+	// Context is the context given by Client.WithContext, or context.Background().
+	Context context.Context
+}
+
+// ResponseInfo describes PRTG's response of a request.
+type ResponseInfo struct {
+	RequestInfo
+	Duration time.Duration
+	// StatusCode is zero when no response is received.
+	StatusCode int
+	// Bytes of the response body
+	Bytes int
+	// ContentType is the response's Content-Type header.
+	ContentType string
+	// Rows is the treesize of JSON table and historic data responses, or -1 if the response has none.
+	Rows int
+}
+
+// Hooks are called by the client around every HTTP request to PRTG, e.g. to trace the requests.
+// Cached responses don't send any request. Any hook may be nil.
+// The hooks may be called concurrently when the client is shared.
+type Hooks struct {
+	// OnRequest is called before the request is sent.
+	OnRequest func(req RequestInfo)
+	// OnResponse is called after the response is read successfully.
+	OnResponse func(res ResponseInfo)
+	// OnError is called when the request fails, including HTTP status other than 200.
+	OnError func(res ResponseInfo, err error)
+	// OnFormatFallback is called when FormatAuto retries the request in the other format.
+	OnFormatFallback func(from, to ResponseFormat, err error)
+}
+
+var lastRequestID uint64
+
+// getHTTPBody sends the request through the client's transport, reporting it to the hooks and logger.
+func (c *Client) getHTTPBody(p string, q *url.Values, u string) ([]byte, *http.Header, error) {
+	return c.sendHTTPRequest(p, q, u, true)
+}
+
+// sendHTTPRequest is getHTTPBody which may return the redirect instead of following it.
+func (c *Client) sendHTTPRequest(p string, q *url.Values, u string, followRedirect bool) ([]byte, *http.Header, error) {
+	req := RequestInfo{
+		ID:       atomic.AddUint64(&lastRequestID, 1),
+		Endpoint: p,
+		ObjectID: -1,
+		Start:    time.Now(),
+		Retry:    c.retry,
+		Context:  c.context(),
+	}
+	if id, err := strconv.ParseInt(q.Get("id"), 10, 64); err == nil {
+		req.ObjectID = id
+	}
+	if c.Hooks.OnRequest != nil {
+		c.Hooks.OnRequest(req)
+	}
+
+	body, header, err := sendHTTPRequest(req.Context, c.Transport, u, c.Timeout, followRedirect)
+	res := ResponseInfo{RequestInfo: req, Duration: time.Since(req.Start), Bytes: len(body), Rows: responseRows(body)}
+	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			res.StatusCode = statusErr.StatusCode
+		}
+		if c.Hooks.OnError != nil {
+			c.Hooks.OnError(res, err)
+		}
+		if c.Logger != nil {
+			c.Logger.Error("PRTG request failed", append(res.keysAndValues(), "error", err)...)
+		}
+		return nil, nil, err
+	}
+	res.StatusCode = http.StatusOK
+	res.ContentType = header.Get("Content-Type")
+	if c.Hooks.OnResponse != nil {
+		c.Hooks.OnResponse(res)
+	}
+	if c.Logger != nil {
+		c.Logger.Debug("PRTG request", res.keysAndValues()...)
+	}
+	return body, header, nil
+}
+
+// treeSizeSearch is the part of a JSON response searched for its treesize,
+// which is at the start of PRTG's responses, or at the end when the keys are sorted.
+const treeSizeSearch = 256
+
+// responseRows returns the treesize of the JSON response, or -1 if it has none.
+func responseRows(body []byte) int {
+	parts := [][]byte{body}
+	if len(body) > 2*treeSizeSearch {
+		parts = [][]byte{body[:treeSizeSearch], body[len(body)-treeSizeSearch:]}
+	}
+	for _, part := range parts {
+		i := bytes.Index(part, []byte(`"treesize"`))
+		if i < 0 {
+			continue
+		}
+		value := bytes.TrimLeft(part[i+len(`"treesize"`):], " \t\r\n:")
+		end := 0
+		for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+			end++
+		}
+		if rows, err := strconv.Atoi(string(value[:end])); err == nil {
+			return rows
+		}
+	}
+	return -1
+}
+
+func (res ResponseInfo) keysAndValues() []interface{} {
+	return []interface{}{
+		"request_id", res.ID,
+		"endpoint", res.Endpoint,
+		"object_id", res.ObjectID,
+		"duration", res.Duration,
+		"status", res.StatusCode,
+		"bytes", res.Bytes,
+	}
+}
+
+// reportFormatFallback reports that FormatAuto retries the request in the other format.
+func (c *Client) reportFormatFallback(from, to ResponseFormat, err error) {
+	if c.Hooks.OnFormatFallback != nil {
+		c.Hooks.OnFormatFallback(from, to, err)
+	}
+	if c.Logger != nil {
+		c.Logger.Warn("PRTG format fallback", "from", from.String(), "to", to.String(), "error", err)
+	}
+}
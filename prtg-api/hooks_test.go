@@ -0,0 +1,161 @@
+package prtg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testLogRecord struct {
+	level         string
+	msg           string
+	keysAndValues []interface{}
+}
+
+type testLogger struct {
+	mu      sync.Mutex
+	records []testLogRecord
+}
+
+func (l *testLogger) log(level, msg string, keysAndValues []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, testLogRecord{level, msg, keysAndValues})
+}
+
+func (l *testLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.log("debug", msg, keysAndValues)
+}
+func (l *testLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.log("warn", msg, keysAndValues)
+}
+func (l *testLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.log("error", msg, keysAndValues)
+}
+
+func TestHooks(t *testing.T) {
+	mux := new(http.ServeMux)
+	// JSON isn't supported, so the client falls back to XML
+	mux.HandleFunc(GetSensorDetailsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc(GetSensorDetailsEndpointXML, func(w http.ResponseWriter, r *http.Request) {
+		responsesXmlOk(w, "/prtg_sensor-detail.xml")
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+
+	var requests []RequestInfo
+	var responses []ResponseInfo
+	var failures []ResponseInfo
+	var fallbacks []string
+	logger := new(testLogger)
+	client := NewClient(httpServer.URL, "user", "secret")
+	client.Logger = logger
+	client.Hooks = Hooks{
+		OnRequest:  func(req RequestInfo) { requests = append(requests, req) },
+		OnResponse: func(res ResponseInfo) { responses = append(responses, res) },
+		OnError:    func(res ResponseInfo, err error) { failures = append(failures, res) },
+		OnFormatFallback: func(from, to ResponseFormat, err error) {
+			fallbacks = append(fallbacks, fmt.Sprintf("%v>%v", from, to))
+		},
+	}
+
+	if _, err := client.GetSensorDetail(9321); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if len(requests) != 2 || requests[0].Endpoint != GetSensorDetailsEndpoint || requests[1].Endpoint != GetSensorDetailsEndpointXML ||
+		requests[0].ObjectID != 9321 || requests[0].ID == requests[1].ID || requests[0].Retry != 0 || requests[1].Retry != 1 {
+		t.Errorf("Unexpected requests: %+v", requests)
+	}
+	if len(failures) != 1 || failures[0].StatusCode != http.StatusNotFound || failures[0].ID != requests[0].ID {
+		t.Errorf("Unexpected failures: %+v", failures)
+	}
+	if len(responses) != 1 || responses[0].StatusCode != http.StatusOK || responses[0].ID != requests[1].ID ||
+		responses[0].Bytes != len(loadfixture("/prtg_sensor-detail.xml")) || responses[0].Duration <= 0 ||
+		responses[0].ContentType != "text/html; charset=UTF-8" || responses[0].Rows != -1 {
+		t.Errorf("Unexpected responses: %+v", responses)
+	}
+	if len(fallbacks) != 1 || fallbacks[0] != "json>xml" {
+		t.Errorf("Unexpected fallbacks: %v", fallbacks)
+	}
+
+	levels := []string{}
+	for _, record := range logger.records {
+		levels = append(levels, record.level)
+		if len(record.keysAndValues)%2 != 0 {
+			t.Errorf("Record %q should have pairs of key and value: %v", record.msg, record.keysAndValues)
+		}
+		if strings.Contains(fmt.Sprint(record.keysAndValues...), "secret") {
+			t.Errorf("Record %q should not contain the password: %v", record.msg, record.keysAndValues)
+		}
+	}
+	if strings.Join(levels, ",") != "error,warn,debug" {
+		t.Errorf("Logged levels are %v instead of error,warn,debug", levels)
+	}
+
+	// Root object
+	requests = nil
+	client.GetSensorTree(0)
+	if len(requests) != 1 || requests[0].ObjectID != 0 || requests[0].Endpoint != GetSensorTreesEndpoint {
+		t.Errorf("Unexpected requests: %+v", requests)
+	}
+}
+
+type testContextKey struct{}
+
+func TestHooksContext(t *testing.T) {
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_sensor-list_9301.json"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+
+	var requests []RequestInfo
+	var responses []ResponseInfo
+	client := NewClient(httpServer.URL, "user", "pass")
+	client.Hooks.OnRequest = func(req RequestInfo) { requests = append(requests, req) }
+	client.Hooks.OnResponse = func(res ResponseInfo) { responses = append(responses, res) }
+
+	ctx := context.WithValue(context.Background(), testContextKey{}, "caller")
+	if _, err := client.WithContext(ctx).GetSensorList(9301, nil); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if _, err := client.GetSensorList(9301, nil); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if len(requests) != 2 || requests[0].Context.Value(testContextKey{}) != "caller" || requests[1].Context.Value(testContextKey{}) != nil {
+		t.Errorf("Only the first request should carry the caller's context: %+v", requests)
+	}
+	if len(responses) != 2 || responses[0].Rows != 11 {
+		t.Errorf("Unexpected responses: %+v", responses)
+	}
+
+	// The requests are cancelled with the context
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.WithContext(cancelled).GetSensorList(9301, nil); err == nil {
+		t.Errorf("Since the context is cancelled, an error should occur.")
+	}
+}
+
+func TestResponseRows(t *testing.T) {
+	padding := strings.Repeat(" ", 2*treeSizeSearch)
+	for body, expected := range map[string]int{
+		`{"prtg-version":"18.2.41.1636","treesize": 11,"sensors":[]}`: 11,
+		`{"sensors":[` + padding + `],"treesize":3}`:                  3,
+		`{"sensors":[],"treesize":` + padding + `3}`:                  -1,
+		`<prtg><treesize>2</treesize></prtg>`:                         -1,
+		``:                                                            -1,
+	} {
+		if rows := responseRows([]byte(body)); rows != expected {
+			t.Errorf("Rows of %q are %v instead of %v", body, rows, expected)
+		}
+	}
+}
@@ -12,35 +12,79 @@ import (
 	"time"
 )
 
-func isContentXML(header http.Header) bool {
-	contentDisposition := header.Get("Content-Type")
-	return contentDisposition == "text/xml; charset=UTF-8" || contentDisposition == "text/html; charset=UTF-8"
+// StatusError is returned when PRTG responds with HTTP status other than 200.
+type StatusError struct {
+	StatusCode int
 }
 
-func getHTTPBody(url string, timeout int64) ([]byte, *http.Header, error) {
-	// Skipping TLS Verification
+func (e *StatusError) Error() string {
+	if e.StatusCode == http.StatusUnauthorized {
+		return fmt.Sprintf("Wrong Username and/or Password | HTTP Response status NOK: %v", e.StatusCode)
+	}
+	return fmt.Sprintf("HTTP Response status NOK: %v", e.StatusCode)
+}
+
+type decodeError struct {
+	format string
+	err    error
+}
+
+func (e *decodeError) Error() string {
+	return fmt.Sprintf("Unable to unmarshal %v response: %v", e.format, e.err)
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
+
+// defaultTransport returns http.DefaultTransport, skipping TLS verification.
+func defaultTransport() http.RoundTripper {
 	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return http.DefaultTransport
+}
+
+// isRedirect reports whether the status code redirects to the Location header.
+func isRedirect(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// sendHTTPRequest sends the GET request within ctx through transport, or the default transport if it's nil.
+// Without followRedirect, the redirect is returned as a successful response,
+// and its Location header tells where PRTG redirects to.
+func sendHTTPRequest(ctx context.Context, transport http.RoundTripper, url string, timeout int64, followRedirect bool) ([]byte, *http.Header, error) {
+	if transport == nil {
+		transport = defaultTransport()
+	}
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, nil, fmt.Errorf("Unable to create GET method: %v", err)
+		return nil, nil, fmt.Errorf("Unable to create GET method: %v", redactSecrets(err.Error()))
 	}
 	req.Header.Set("User-Agent", userAgent)
-	ctx, cancel := context.WithTimeout(req.Context(), time.Duration(timeout)*time.Millisecond)
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
 	defer cancel()
 	req = req.WithContext(ctx)
 
-	res, err := http.DefaultClient.Do(req)
+	client := &http.Client{Transport: transport}
+	if !followRedirect {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	res, err := client.Do(req)
 	if err != nil {
-		return nil, nil, fmt.Errorf("Unable to create HTTP request: %v", err)
+		// The error contains the URL
+		return nil, nil, fmt.Errorf("Unable to create HTTP request: %v", redactSecrets(err.Error()))
 	}
 	defer res.Body.Close()
 
-	if res.StatusCode == 401 {
-		return nil, nil, fmt.Errorf("Wrong Username and/or Password | HTTP Response status NOK: %v", res.StatusCode)
-	}
-	if res.StatusCode != 200 {
-		return nil, nil, fmt.Errorf("HTTP Response status NOK: %v", res.StatusCode)
+	if res.StatusCode != 200 && (followRedirect || !isRedirect(res.StatusCode)) {
+		return nil, nil, &StatusError{StatusCode: res.StatusCode}
 	}
 
 	body, err := ioutil.ReadAll(res.Body)
@@ -50,27 +94,26 @@ func getHTTPBody(url string, timeout int64) ([]byte, *http.Header, error) {
 	return body, &res.Header, nil
 }
 
-func getPrtgResponse(url string, timeout int64, v interface{}) error {
-	body, header, err := getHTTPBody(url, timeout)
-	if err != nil {
-		return err
-	}
+func decodePrtgResponse(body []byte, header *http.Header, v interface{}) error {
 	// Unmarshal XML
-	if isContentXML(*header) {
+	if isContentXML(*header, body) {
 		// remove CDATA indentiation
 		modData := string(body)
 		decoder := xml.NewDecoder(strings.NewReader(modData))
 		decoder.Strict = false
-		err = decoder.Decode(&v)
+		err := decoder.Decode(&v)
 		if err != nil {
-			return fmt.Errorf("Unable to unmarshal xml response: %v", err)
+			return &decodeError{format: "xml", err: err}
 		}
 		return nil
 	}
 	// Unmarshal JSON for default
-	err = json.Unmarshal(body, &v)
+	err := json.Unmarshal(body, &v)
 	if err != nil {
-		return fmt.Errorf("Unable to unmarshal json response: %v", err)
+		// Retry after fixing PRTG's known JSON quirks
+		if errLenient := json.Unmarshal(sanitizePrtgJSON(body), &v); errLenient != nil {
+			return &decodeError{format: "json", err: err}
+		}
 	}
 	return nil
 }
@@ -1,13 +1,14 @@
 package prtg
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"testing"
 )
 
-func TestGetHttpBody(t *testing.T) {
+func TestSendHTTPRequest(t *testing.T) {
 	var url string
 	var timeout int64
 	var err error
@@ -15,14 +16,14 @@ func TestGetHttpBody(t *testing.T) {
 	// Wrong written url
 	url = " http://localhost"
 	timeout = 10000
-	_, _, err = getHTTPBody(url, timeout)
+	_, _, err = sendHTTPRequest(context.Background(), nil, url, timeout, true)
 	if err == nil {
 		t.Errorf("It Should be error (at NewRequest()) if url %v", url)
 	}
 
 	// When server not found or inactive
 	url = "http://localhost"
-	_, _, err = getHTTPBody(url, timeout)
+	_, _, err = sendHTTPRequest(context.Background(), nil, url, timeout, true)
 	if err == nil {
 		t.Errorf("It Should be error (at Send Request) if server down: %v", err)
 	}
@@ -42,7 +43,7 @@ func TestRespStatusCode(t *testing.T) {
 	path := "wrong/path"
 	u := fmt.Sprintf("%v/%v", serverURL, path)
 	var timeout int64 = 10000
-	_, _, err := getHTTPBody(u, timeout)
+	_, _, err := sendHTTPRequest(context.Background(), nil, u, timeout, true)
 	if err == nil {
 		t.Errorf("%v", err)
 	}
@@ -62,7 +63,7 @@ func TestUnauthorizedAccess(t *testing.T) {
 	path := GetSensorDetailsEndpoint
 	u := fmt.Sprintf("%v/%v", serverURL, path)
 	var timeout int64 = 10000
-	_, _, err := getHTTPBody(u, timeout)
+	_, _, err := sendHTTPRequest(context.Background(), nil, u, timeout, true)
 	if err == nil {
 		t.Errorf("%v", err)
 	}
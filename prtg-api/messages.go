@@ -0,0 +1,115 @@
+package prtg
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrtgMessage contains an entry of PRTG's log, e.g. a sensor's status change.
+type PrtgMessage struct {
+	ObjectId int64
+	// DateTime in UTC
+	DateTime time.Time
+	// Parent is the name of the object's parent, e.g. the sensor's device
+	Parent string
+	// Type is the object's type, e.g. "Ping"
+	Type string
+	Name string
+	// Status is the log's status, e.g. "Down", "Up", or "Acknowledged"
+	Status  string
+	Message string
+}
+
+// prtgMessageRow contains property for each log entry within list API.
+type prtgMessageRow struct {
+	ObjectId    int64      `json:"objid"`
+	DateTimeRaw prtgScalar `json:"datetime_raw"`
+	Parent      string     `json:"parent"`
+	Type        string     `json:"type"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Message     string     `json:"message_raw"`
+}
+
+var defaultMessageListCols = []string{"objid", "datetime", "parent", "type", "name", "status", "message"}
+
+// maxMessages is the maximum number of log entries returned by PRTG at once.
+const maxMessages = 50000
+
+// typed parses the row into PrtgMessage.
+func (row *prtgMessageRow) typed() (*PrtgMessage, error) {
+	days, err := strconv.ParseFloat(trimWeirdCharacter(string(row.DateTimeRaw)), 64)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse datetime of message of %v: %v", row.ObjectId, err)
+	}
+	return &PrtgMessage{
+		ObjectId: row.ObjectId,
+		DateTime: ConvertPrtgDateTime(days),
+		Parent:   trimWeirdCharacter(row.Parent),
+		Type:     trimWeirdCharacter(row.Type),
+		Name:     trimWeirdCharacter(row.Name),
+		Status:   trimWeirdCharacter(row.Status),
+		Message:  trimWeirdCharacter(row.Message),
+	}, nil
+}
+
+// GetMessages returns the log entries of the object and the objects within it,
+// logged between the start and end of date's boundaries, oldest first.
+// A period without entries returns an empty list rather than an error.
+func (c *Client) GetMessages(id int64, startDate, endDate time.Time) ([]PrtgMessage, error) {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return nil, fmt.Errorf("Id should be more than or equals to zero")
+	}
+	for _, date := range []time.Time{startDate, endDate} {
+		if err := validateDateFormat(date); err != nil {
+			return nil, err
+		}
+	}
+	if !startDate.Before(endDate) {
+		return nil, fmt.Errorf("Start date should be before end date")
+	}
+
+	filters := url.Values{}
+	filters.Set("filter_dstart", startDate.Format(dateFormat))
+	filters.Set("filter_dend", endDate.Format(dateFormat))
+	filters.Set("count", fmt.Sprintf("%v", maxMessages))
+	content := "messages"
+	messageListResp, err := c.getFilteredTableList(id, content, defaultMessageListCols, filters)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get message list data: %v", err)
+	}
+
+	messages := make([]PrtgMessage, 0, len(messageListResp.Messages))
+	for _, row := range messageListResp.Messages {
+		message, err := row.typed()
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *message)
+	}
+	// PRTG returns the newest entry first
+	sort.SliceStable(messages, func(i, j int) bool { return messages[i].DateTime.Before(messages[j].DateTime) })
+	return messages, nil
+}
+
+// isDownStatus reports whether the log's status means the object went down,
+// e.g. "Down" or "Down (Partial)".
+func isDownStatus(status string) bool {
+	return strings.HasPrefix(status, "Down")
+}
+
+// isRecoveryStatus reports whether the log's status means the object left the down status.
+// Other log entries, e.g. "Acknowledged" or "Settings changed", don't change the status.
+func isRecoveryStatus(status string) bool {
+	switch status {
+	case "Up", "Warning", "Unusual", "Paused", "Resumed":
+		return true
+	}
+	return false
+}
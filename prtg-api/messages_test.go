@@ -0,0 +1,68 @@
+package prtg
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestGetMessages(t *testing.T) {
+	var query url.Values
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.FormValue("content") != "messages" {
+			return
+		}
+		if r.FormValue("id") != "2001" {
+			fmt.Fprint(w, `{"prtg-version":"18.2.41.1652","treesize":0,"messages":[]}`)
+			return
+		}
+		fmt.Fprint(w, loadfixture("/prtg_message-list_2001.json"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	startDate := time.Date(2018, time.June, 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.Add(6 * time.Hour)
+	messages, err := client.GetMessages(2001, startDate, endDate)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if query.Get("filter_dstart") != "2018-06-01-00-00-00" || query.Get("filter_dend") != "2018-06-01-06-00-00" ||
+		query.Get("count") != "50000" {
+		t.Errorf("Unexpected query: %v", query)
+	}
+	if len(messages) != 7 {
+		t.Errorf("There should be 7 messages instead of %v", len(messages))
+		return
+	}
+	// The oldest message comes first
+	first := messages[0]
+	if first.ObjectId != 2001 || first.Status != "Down (Partial)" || first.Message != "Request timed out (ping: 10 s)" ||
+		first.Parent != "Router" || first.Type != "Ping" || first.Name != "Ping" ||
+		!first.DateTime.Equal(startDate.Add(90*time.Minute)) {
+		t.Errorf("Unexpected message: %+v", first)
+	}
+	if last := messages[6]; !last.DateTime.Equal(startDate.Add(350 * time.Minute)) {
+		t.Errorf("Last message is logged at %v instead of %v", last.DateTime, startDate.Add(350*time.Minute))
+	}
+
+	// No message isn't an error
+	messages, err = client.GetMessages(9000, startDate, endDate)
+	if err != nil || len(messages) != 0 {
+		t.Errorf("There should be no message instead of %v, error: %v", len(messages), err)
+	}
+	if _, err := client.GetMessages(-1, startDate, endDate); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+	if _, err := client.GetMessages(2001, endDate, startDate); err == nil {
+		t.Errorf("Since the start date is after the end date, an error should occur.")
+	}
+}
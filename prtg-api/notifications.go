@@ -0,0 +1,97 @@
+package prtg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrtgNotification contains a notification template, which is sent by the triggers.
+type PrtgNotification struct {
+	ObjectId int64
+	Name     string
+	// Active is false when the template is paused, and its triggers send nothing
+	Active bool
+	Tags   []string
+	// Actions are the template's enabled actions, e.g. "Send Email"
+	Actions []string
+}
+
+// prtgNotificationRow contains property for each notification template within list API.
+type prtgNotificationRow struct {
+	ObjectId int64  `json:"objid"`
+	Name     string `json:"name"`
+	// PRTG returns -1, 1, or true for an active template
+	ActiveRaw prtgScalar `json:"active_raw"`
+	Tags      string     `json:"tags"`
+	Summary   string     `json:"summary"`
+}
+
+var defaultNotificationListCols = []string{"objid", "name", "active", "tags", "summary"}
+
+// typed parses the row into PrtgNotification.
+func (row *prtgNotificationRow) typed() (*PrtgNotification, error) {
+	notification := PrtgNotification{
+		ObjectId: row.ObjectId,
+		Name:     trimWeirdCharacter(row.Name),
+		Tags:     strings.Fields(row.Tags),
+		Actions:  []string{},
+	}
+	active := trimWeirdCharacter(string(row.ActiveRaw))
+	if active == "-1" {
+		notification.Active = true
+	} else {
+		var err error
+		if notification.Active, err = parsePrtgBool(active); err != nil {
+			return nil, fmt.Errorf("Unable to parse active of notification %v: %v", row.ObjectId, err)
+		}
+	}
+	for _, action := range strings.Split(row.Summary, ",") {
+		if action = trimWeirdCharacter(action); !isPrtgEmpty(action) {
+			notification.Actions = append(notification.Actions, action)
+		}
+	}
+	return &notification, nil
+}
+
+// GetNotifications returns the notification templates configured in PRTG.
+// A server without notification templates returns an empty list rather than an error.
+func (c *Client) GetNotifications() ([]PrtgNotification, error) {
+	// Notification templates don't belong to the object tree, so the root is requested
+	content := "notifications"
+	notificationListResp, err := c.getTableList(0, content, defaultNotificationListCols)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get notification list data: %v", err)
+	}
+
+	notifications := make([]PrtgNotification, 0, len(notificationListResp.Notifications))
+	for _, row := range notificationListResp.Notifications {
+		notification, err := row.typed()
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, *notification)
+	}
+	return notifications, nil
+}
+
+// TestNotification sends the notification template's test message through every enabled action.
+// PRTG sends it in background, so an error of the actions themselves is not returned.
+func (c *Client) TestNotification(id int64) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return fmt.Errorf("Id should be more than or equals to zero")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	u, err := c.getCompleteUrl(TestNotificationEndpoint, q)
+	if err != nil {
+		return fmt.Errorf("Unable to test notification: %v", err)
+	}
+	// Testing changes nothing, so the cache is kept, while the test itself should never be cached
+	if _, _, err := c.getHTTPBody(TestNotificationEndpoint, q, u); err != nil {
+		return fmt.Errorf("Unable to test notification: %v", err)
+	}
+	return nil
+}
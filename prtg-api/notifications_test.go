@@ -0,0 +1,101 @@
+package prtg
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetNotifications(t *testing.T) {
+	empty := false
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.FormValue("content") != "notifications" || r.FormValue("id") != "0" {
+			return
+		}
+		if empty {
+			fmt.Fprint(w, `{"prtg-version":"18.2.41.1652","treesize":0,"notifications":[]}`)
+			return
+		}
+		fmt.Fprint(w, loadfixture("/prtg_notification-list_0.json"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	notifications, err := client.GetNotifications()
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	expected := []PrtgNotification{
+		{ObjectId: 300, Name: "Email to Admin", Active: true, Tags: []string{"email", "noc"},
+			Actions: []string{"Send Email", "Add Entry to Event Log"}},
+		{ObjectId: 301, Name: "SMS to On-Call", Active: true, Tags: []string{}, Actions: []string{"Send SMS/Pager Message"}},
+		{ObjectId: 302, Name: "Ticket to NOC", Active: false, Tags: []string{"ticket"}, Actions: []string{}},
+	}
+	if !reflect.DeepEqual(notifications, expected) {
+		t.Errorf("Notifications are %+v instead of %+v", notifications, expected)
+	}
+
+	empty = true
+	if notifications, err := client.GetNotifications(); err != nil || notifications == nil || len(notifications) != 0 {
+		t.Errorf("There should be an empty list instead of %v, error: %v", notifications, err)
+	}
+}
+
+func TestTestNotification(t *testing.T) {
+	tested := ""
+	tests, statuses := 0, 0
+	mux := new(http.ServeMux)
+	mux.HandleFunc(TestNotificationEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		tests++
+		tested = r.FormValue("id")
+		if tested == "9000" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc(GetStatusEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		statuses++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_status.json"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+	client.EnableCache(CacheConfig{DefaultTTL: time.Minute})
+
+	if err := client.TestNotification(300); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if tested != "300" {
+		t.Errorf("Notification %v is tested instead of 300", tested)
+	}
+
+	// The test is sent every time, and keeps the cached responses
+	if _, err := client.GetStatus(); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if err := client.TestNotification(300); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if _, err := client.GetStatus(); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if tests != 2 || statuses != 1 {
+		t.Errorf("Notification should be tested twice and status requested once instead of %v and %v", tests, statuses)
+	}
+	if err := client.TestNotification(9000); err == nil {
+		t.Errorf("Since the notification doesn't exist, an error should occur.")
+	}
+	if err := client.TestNotification(-1); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+}
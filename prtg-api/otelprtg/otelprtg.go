@@ -0,0 +1,160 @@
+// Package otelprtg instruments the PRTG client with OpenTelemetry.
+//
+// Instrument installs hooks on a prtg.Client, so every HTTP request it sends to PRTG
+// creates a client span named after the PRTG endpoint, and is recorded
+// in the prtg.client.request.duration histogram. The span's parent is taken
+// from the context given by prtg.Client.WithContext.
+//
+//	client := prtg.NewClient(server, username, password)
+//	otelprtg.Instrument(client)
+//	sensors, err := client.WithContext(ctx).GetSensorList(9301, nil)
+//
+// It lives in its own module, so the prtg package doesn't depend on OpenTelemetry.
+package otelprtg
+
+import (
+	"context"
+	"sync"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracer and meter.
+const ScopeName = "github.com/haidlir/golang-prtg-api-wrapper/prtg-api/otelprtg"
+
+// Attribute keys of the spans and metrics
+const (
+	EndpointKey    = attribute.Key("prtg.endpoint")
+	ObjectIDKey    = attribute.Key("prtg.object.id")
+	ContentTypeKey = attribute.Key("prtg.response.content_type")
+	RowCountKey    = attribute.Key("prtg.response.row_count")
+	RetryCountKey  = attribute.Key("prtg.retry_count")
+	StatusCodeKey  = attribute.Key("http.response.status_code")
+)
+
+// RequestDurationMetric is the histogram of every HTTP request's duration, in seconds.
+const RequestDurationMetric = "prtg.client.request.duration"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+// WithTracerProvider uses the provider instead of the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(cfg *config) {
+		cfg.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider uses the provider instead of the global one.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(cfg *config) {
+		cfg.meterProvider = provider
+	}
+}
+
+// instrumentation keeps the spans of the requests in flight by request's id.
+type instrumentation struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	spans    sync.Map
+}
+
+// Instrument traces every request of client, including the requests of its copies made afterward
+// by prtg.Client.WithContext. The client's own hooks are still called.
+func Instrument(client *prtg.Client, opts ...Option) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	duration, err := cfg.meterProvider.Meter(ScopeName).Float64Histogram(RequestDurationMetric,
+		metric.WithDescription("Duration of HTTP requests sent to PRTG."),
+		metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	inst := &instrumentation{
+		tracer:   cfg.tracerProvider.Tracer(ScopeName),
+		duration: duration,
+	}
+
+	hooks := client.Hooks
+	client.Hooks.OnRequest = func(req prtg.RequestInfo) {
+		inst.start(req)
+		if hooks.OnRequest != nil {
+			hooks.OnRequest(req)
+		}
+	}
+	client.Hooks.OnResponse = func(res prtg.ResponseInfo) {
+		inst.end(res, nil)
+		if hooks.OnResponse != nil {
+			hooks.OnResponse(res)
+		}
+	}
+	client.Hooks.OnError = func(res prtg.ResponseInfo, err error) {
+		inst.end(res, err)
+		if hooks.OnError != nil {
+			hooks.OnError(res, err)
+		}
+	}
+}
+
+func (inst *instrumentation) start(req prtg.RequestInfo) {
+	attrs := []attribute.KeyValue{EndpointKey.String(req.Endpoint), RetryCountKey.Int(req.Retry)}
+	if req.ObjectID >= 0 {
+		attrs = append(attrs, ObjectIDKey.Int64(req.ObjectID))
+	}
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, _ = inst.tracer.Start(ctx, req.Endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(req.Start),
+		trace.WithAttributes(attrs...))
+	inst.spans.Store(req.ID, ctx)
+}
+
+func (inst *instrumentation) end(res prtg.ResponseInfo, err error) {
+	value, ok := inst.spans.LoadAndDelete(res.ID)
+	if !ok {
+		return
+	}
+	ctx := value.(context.Context)
+	span := trace.SpanFromContext(ctx)
+	if res.StatusCode != 0 {
+		span.SetAttributes(StatusCodeKey.Int(res.StatusCode))
+	}
+	if res.ContentType != "" {
+		span.SetAttributes(ContentTypeKey.String(res.ContentType))
+	}
+	if res.Rows >= 0 {
+		span.SetAttributes(RowCountKey.Int(res.Rows))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(res.Start.Add(res.Duration)))
+
+	if inst.duration == nil {
+		return
+	}
+	attrs := []attribute.KeyValue{EndpointKey.String(res.Endpoint)}
+	if res.StatusCode != 0 {
+		attrs = append(attrs, StatusCodeKey.Int(res.StatusCode))
+	}
+	inst.duration.Record(ctx, res.Duration.Seconds(), metric.WithAttributes(attrs...))
+}
@@ -0,0 +1,167 @@
+package otelprtg
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api/prtgtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setup() (*prtgtest.Server, *tracetest.InMemoryExporter, *sdkmetric.ManualReader, *prtg.Client) {
+	server := prtgtest.NewServer()
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	client := prtg.NewClient(server.URL, prtgtest.DefaultUsername, prtgtest.DefaultPassword)
+	Instrument(client,
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	return server, exporter, reader, client
+}
+
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestInstrumentSpans(t *testing.T) {
+	server, exporter, _, client := setup()
+	defer server.Close()
+	device := server.AddDevice(prtgtest.RootID, "Router", "10.0.0.1")
+	server.AddSensor(device, prtgtest.Sensor{Name: "Ping"})
+	server.AddSensor(device, prtgtest.Sensor{Name: "HTTP"})
+
+	sensors, err := client.GetSensorList(device, nil)
+	if err != nil || len(sensors) != 2 {
+		t.Errorf("There should be 2 sensors instead of %v, error: %v", len(sensors), err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("There should be 1 span instead of %v", len(spans))
+	}
+	attrs := spanAttributes(spans[0])
+	if spans[0].Name != prtg.GetTableListsEndpoint || attrs[ObjectIDKey].AsInt64() != device ||
+		attrs[StatusCodeKey].AsInt64() != http.StatusOK || attrs[RowCountKey].AsInt64() != 2 ||
+		attrs[RetryCountKey].AsInt64() != 0 || attrs[ContentTypeKey].AsString() != "application/json; charset=UTF-8" {
+		t.Errorf("Unexpected span %v: %v", spans[0].Name, attrs)
+	}
+
+	// JSON is unavailable, so the client retries in XML
+	exporter.Reset()
+	server.InjectFault(prtg.GetSensorDetailsEndpoint, prtgtest.Fault{StatusCode: http.StatusNotFound})
+	if _, err := client.GetPrtgVersion(); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	spans = exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("There should be 2 spans instead of %v", len(spans))
+	}
+	attrs = spanAttributes(spans[0])
+	if spans[0].Name != prtg.GetSensorDetailsEndpoint || spans[0].Status.Code != codes.Error ||
+		attrs[StatusCodeKey].AsInt64() != http.StatusNotFound || attrs[RetryCountKey].AsInt64() != 0 {
+		t.Errorf("Unexpected span %v: %v", spans[0].Name, attrs)
+	}
+	attrs = spanAttributes(spans[1])
+	if spans[1].Name != prtg.GetSensorDetailsEndpointXML || attrs[RetryCountKey].AsInt64() != 1 {
+		t.Errorf("Unexpected span %v: %v", spans[1].Name, attrs)
+	}
+	if _, ok := attrs[RowCountKey]; ok {
+		t.Errorf("Span %v should not have row count", spans[1].Name)
+	}
+	if _, ok := spanAttributes(spans[0])[ObjectIDKey]; !ok {
+		t.Errorf("Span %v should have the root's object id", spans[0].Name)
+	}
+
+	// The requests of a call spanning several chunks aren't retries
+	exporter.Reset()
+	server.ClearFaults()
+	sensor := server.AddSensor(device, prtgtest.Sensor{Name: "Traffic"})
+	start := time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC)
+	server.AddHistoricData(sensor, prtgtest.HistoricRecord{Time: start, Coverage: 100, Values: map[string]float64{"Traffic In": 1}})
+	if _, err := client.GetHistoricDataRange(sensor, 300, start, start.AddDate(0, 0, 65)); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	spans = exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("There should be 3 spans instead of %v", len(spans))
+	}
+	for _, span := range spans {
+		if retry := spanAttributes(span)[RetryCountKey].AsInt64(); retry != 0 {
+			t.Errorf("Retry count of span %v is %v instead of 0", span.Name, retry)
+		}
+	}
+}
+
+func TestInstrumentParentSpan(t *testing.T) {
+	server, exporter, _, client := setup()
+	defer server.Close()
+	device := server.AddDevice(prtgtest.RootID, "Router", "10.0.0.1")
+
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	if err := client.WithContext(ctx).PauseObject(device, "maintenance"); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Name != prtg.PauseObjectEndpoint ||
+		spans[0].Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("The request's span should be the child of the parent: %+v", spans)
+	}
+}
+
+func TestInstrumentDurationHistogram(t *testing.T) {
+	server := prtgtest.NewServer()
+	defer server.Close()
+	reader := sdkmetric.NewManualReader()
+	client := prtg.NewClient(server.URL, prtgtest.DefaultUsername, prtgtest.DefaultPassword)
+
+	// The original hooks are kept
+	var responses int
+	client.Hooks.OnResponse = func(res prtg.ResponseInfo) { responses++ }
+	Instrument(client, WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	for i := 0; i < 3; i++ {
+		client.GetSensorTree(prtgtest.RootID)
+	}
+	if responses != 3 {
+		t.Errorf("The client's hook should be called 3 times instead of %v", responses)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Unable to collect metrics: %v", err)
+	}
+	var count uint64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != RequestDurationMetric {
+				continue
+			}
+			histogram, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok {
+				t.Fatalf("%v should be a histogram of float64", m.Name)
+			}
+			for _, point := range histogram.DataPoints {
+				if endpoint, _ := point.Attributes.Value(EndpointKey); endpoint.AsString() != prtg.GetSensorTreesEndpoint {
+					t.Errorf("Unexpected endpoint: %v", endpoint.AsString())
+				}
+				count += point.Count
+			}
+		}
+	}
+	if count != 3 {
+		t.Errorf("There should be 3 requests recorded instead of %v", count)
+	}
+}
@@ -0,0 +1,64 @@
+package prtg
+
+import (
+	"fmt"
+	"strings"
+)
+
+type prtgObjectPropertyResponse struct {
+	PrtgVersion string `xml:"version"`
+	Result      string `xml:"result"`
+}
+
+// propertyNotFound is PRTG's result of a property which the object doesn't have
+const propertyNotFound = "(Property not found)"
+
+// GetObjectProperty returns the property of the object, e.g. "name", "tags", "host", or "interval".
+// The property's name is the one of PRTG's settings page, without the trailing underscore.
+func (c *Client) GetObjectProperty(id int64, name string) (string, error) {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return "", fmt.Errorf("Id should be more than or equals to zero")
+	}
+	if strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("Property's name should not be empty")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("name", name)
+	q.Set("show", "nohtmlencode")
+	var propertyResp prtgObjectPropertyResponse
+	if err := c.getPrtgResponse(GetObjectPropertyEndpoint, q, &propertyResp); err != nil {
+		return "", fmt.Errorf("Unable to get object property: %v", err)
+	}
+	if propertyResp.PrtgVersion == "" {
+		return "", fmt.Errorf("Unable to get object property: Response is not PRTG's property")
+	}
+	if trimWeirdCharacter(propertyResp.Result) == propertyNotFound {
+		return "", fmt.Errorf("Unable to get object property: Object %v doesn't have property %v", id, name)
+	}
+	return propertyResp.Result, nil
+}
+
+// SetObjectProperty changes the property of the object, see GetObjectProperty for the property's name.
+func (c *Client) SetObjectProperty(id int64, name, value string) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return fmt.Errorf("Id should be more than or equals to zero")
+	}
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("Property's name should not be empty")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("name", name)
+	q.Set("value", value)
+	if err := c.requestAction(SetObjectPropertyEndpoint, q); err != nil {
+		return fmt.Errorf("Unable to set object property: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package prtg
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestObjectProperty(t *testing.T) {
+	properties := map[string]string{"name": "Router", "tags": "cisco core", "comments": "<b>rack 4</b>"}
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetObjectPropertyEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		value, ok := properties[r.FormValue("name")]
+		if !ok || r.FormValue("id") != "2050" {
+			value = "(Property not found)"
+		}
+		fmt.Fprintf(w, "<?xml version=\"1.0\" encoding=\"UTF-8\" ?>\n<prtg>\n<version>18.2.41.1652</version>\n<result><![CDATA[%v]]></result>\n</prtg>", value)
+	})
+	mux.HandleFunc(SetObjectPropertyEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("id") != "2050" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		properties[r.FormValue("name")] = r.FormValue("value")
+		w.WriteHeader(http.StatusOK)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	value, err := client.GetObjectProperty(2050, "comments")
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	} else if value != "<b>rack 4</b>" {
+		t.Errorf("Property is %v instead of <b>rack 4</b>", value)
+	}
+	if err := client.SetObjectProperty(2050, "tags", "cisco edge"); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if value, _ := client.GetObjectProperty(2050, "tags"); value != "cisco edge" {
+		t.Errorf("Property is %v instead of cisco edge", value)
+	}
+
+	if _, err := client.GetObjectProperty(2050, "unknown"); err == nil {
+		t.Errorf("Since the property doesn't exist, an error should occur.")
+	}
+	if err := client.SetObjectProperty(9000, "tags", ""); err == nil {
+		t.Errorf("Since the object doesn't exist, an error should occur.")
+	}
+	if _, err := client.GetObjectProperty(-1, "name"); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+	if err := client.SetObjectProperty(2050, " ", "value"); err == nil {
+		t.Errorf("Since the name is empty, an error should occur.")
+	}
+}
@@ -1,8 +1,12 @@
 package prtg
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"path"
 	"strings"
 	"time"
 )
@@ -24,6 +28,38 @@ type Client struct {
 
 	// Timeout Context in millisecond
 	Timeout int64
+
+	// Transport sends the HTTP requests. When nil, http.DefaultTransport is used
+	// with TLS verification skipped. See Recorder and Replayer.
+	Transport http.RoundTripper
+
+	// Format of the response for endpoints available both in JSON and XML.
+	// The default value is FormatAuto.
+	Format ResponseFormat
+
+	// Logger receives a record per HTTP request, nil to disable logging.
+	Logger Logger
+
+	// Hooks are called around every HTTP request.
+	Hooks Hooks
+
+	// Response cache, nil when disabled
+	cache *responseCache
+
+	// Format detected by FormatAuto, nil when the client isn't created by NewClient
+	detected *formatDetection
+
+	// Sensor types kept per server's version, nil when the client isn't created by NewClient
+	sensorTypes *sensorTypeCatalog
+
+	// Context of the requests, set by WithContext
+	ctx context.Context
+
+	// retry is the number of preceding attempts of the call, set on the copy which falls back to the other format
+	retry int
+
+	// pollInterval of the sensor creation's progress, zero for addSensorPollInterval
+	pollInterval time.Duration
 }
 
 var (
@@ -37,6 +73,7 @@ var (
 		"undefinedsens"}
 	defaultGroupListCols []string = []string{"objid", "probe", "group", "name", "downsens", "partialdownsens", "downacksens",
 		"upsens", "warnsens", "pausedsens", "unusualsens", "undefinedsens"}
+	defaultChannelListCols []string = []string{"objid", "name", "lastvalue"}
 )
 
 const (
@@ -50,8 +87,58 @@ const (
 	GetHistoricDatasEndpoint = "/api/historicdata.json"
 	// GetHistoricDatasEndpointXML contains path to historic data API endpoint in XML format
 	GetHistoricDatasEndpointXML = "/api/historicdata.xml"
+	// GetHistoricDatasEndpointCSV contains path to historic data API endpoint in CSV format
+	GetHistoricDatasEndpointCSV = "/api/historicdata.csv"
 	// GetSensorTreesEndpoint contains path to serson tree API endpoint
 	GetSensorTreesEndpoint = "/api/table.xml"
+	// PauseObjectEndpoint contains path to pause and resume API endpoint
+	PauseObjectEndpoint = "/api/pause.htm"
+	// PauseObjectForEndpoint contains path to pause for a duration API endpoint
+	PauseObjectForEndpoint = "/api/pauseobjectfor.htm"
+	// AcknowledgeAlarmEndpoint contains path to acknowledge alarm API endpoint
+	AcknowledgeAlarmEndpoint = "/api/acknowledgealarm.htm"
+	// GetChartEndpointPNG contains path to chart API endpoint in PNG format
+	GetChartEndpointPNG = "/chart.png"
+	// GetChartEndpointSVG contains path to chart API endpoint in SVG format
+	GetChartEndpointSVG = "/chart.svg"
+	// RunReportEndpoint contains path to run report API endpoint
+	RunReportEndpoint = "/api/runreport.htm"
+	// DownloadReportEndpoint contains path to download the generated report API endpoint
+	DownloadReportEndpoint = "/api/downloadreport.htm"
+	// GetStatusEndpoint contains path to system status API endpoint
+	GetStatusEndpoint = "/api/status.json"
+	// AddGroupEndpoint contains path to add group endpoint
+	AddGroupEndpoint = "/addgroup2.htm"
+	// AddDeviceEndpoint contains path to add device endpoint
+	AddDeviceEndpoint = "/adddevice2.htm"
+	// AddSensorStartEndpoint contains path to start adding sensor endpoint, which returns the tmpid
+	AddSensorStartEndpoint = "/addsensor2.htm"
+	// AddSensorProgressEndpoint contains path to the progress of adding sensor API endpoint
+	AddSensorProgressEndpoint = "/api/getaddsensorprogress.htm"
+	// AddSensorEndpoint contains path to add sensor endpoint
+	AddSensorEndpoint = "/addsensor5.htm"
+	// AutoDiscoveryEndpoint contains path to run auto-discovery API endpoint
+	AutoDiscoveryEndpoint = "/api/discovernow.htm"
+	// GetSensorTypesEndpoint contains path to the sensor types of an object API endpoint
+	GetSensorTypesEndpoint = "/api/sensortypes.json"
+	// GetSensorTypesInUseEndpoint contains path to the sensor types in use API endpoint
+	GetSensorTypesInUseEndpoint = "/api/sensortypesinuse.json"
+	// GetTriggerTypesEndpoint contains path to the supported trigger types of an object API endpoint
+	GetTriggerTypesEndpoint = "/api/triggers.json"
+	// EditSettingsEndpoint contains path to edit object's settings endpoint, used for triggers
+	EditSettingsEndpoint = "/editsettings"
+	// RemoveTriggerEndpoint contains path to remove trigger endpoint
+	RemoveTriggerEndpoint = "/deletesub.htm"
+	// TestNotificationEndpoint contains path to test notification API endpoint
+	TestNotificationEndpoint = "/api/notificationtest.htm"
+	// GetObjectPropertyEndpoint contains path to get object property API endpoint
+	GetObjectPropertyEndpoint = "/api/getobjectproperty.htm"
+	// SetObjectPropertyEndpoint contains path to set object property API endpoint
+	SetObjectPropertyEndpoint = "/api/setobjectproperty.htm"
+	// MoveObjectEndpoint contains path to move object endpoint
+	MoveObjectEndpoint = "/moveobjectnow.htm"
+	// DeleteObjectEndpoint contains path to delete object API endpoint
+	DeleteObjectEndpoint = "/api/deleteobject.htm"
 	// Some Private constant.
 	userAgent = "golang-prtg-api"
 )
@@ -67,6 +154,8 @@ func NewClient(server, username, password string) *Client {
 	instance.Username = username
 	instance.Password = password
 	instance.Timeout = 10000
+	instance.detected = new(formatDetection)
+	instance.sensorTypes = new(sensorTypeCatalog)
 	return instance
 }
 
@@ -81,9 +170,27 @@ func NewClientWithHashedPass(server, username, passwordHash string) *Client {
 	instance.Username = username
 	instance.PasswordHash = passwordHash
 	instance.Timeout = 10000
+	instance.detected = new(formatDetection)
+	instance.sensorTypes = new(sensorTypeCatalog)
 	return instance
 }
 
+// WithContext returns a shallow copy of the client whose requests carry ctx,
+// so they're cancelled with it, and the hooks receive it, e.g. to find the caller's trace span.
+// The copy shares the cache, detected format, and sensor types of the client.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	client := *c
+	client.ctx = ctx
+	return &client
+}
+
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
 // SetContextTimeout configures the client timeout value in millisecond format.
 func (c *Client) SetContextTimeout(timeout int64) {
 	if timeout <= 0 {
@@ -108,24 +215,44 @@ func (c *Client) getTemplateUrlQuery() *url.Values {
 func (c *Client) getCompleteUrl(p string, q *url.Values) (string, error) {
 	u, err := url.Parse(c.Server)
 	if err != nil {
-		return "", fmt.Errorf("Unable to parse url: %v", err)
+		return "", fmt.Errorf("Unable to parse url: %v", redactSecrets(err.Error()))
 	}
 	u.Path = p
 	u.RawQuery = q.Encode()
 	return u.String(), nil
 }
 
-func (c *Client) getSensorDetail(q *url.Values) (*prtgSensorDetailsResponse, error) {
-	p := GetSensorDetailsEndpoint
-
+func (c *Client) getResponseBody(p string, q *url.Values) ([]byte, *http.Header, error) {
 	// Complete URL
 	u, err := c.getCompleteUrl(p, q)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	if c.cache == nil {
+		return c.getHTTPBody(p, q, u)
+	}
+	return c.cache.getHTTPBody(c.getCacheKey(p, q), p, func() ([]byte, *http.Header, error) {
+		return c.getHTTPBody(p, q, u)
+	})
+}
+
+func (c *Client) getPrtgResponse(p string, q *url.Values, v interface{}) error {
+	body, header, err := c.getResponseBody(p, q)
+	if err != nil {
+		return err
+	}
+	// XML only endpoint should not be decoded as JSON
+	if path.Ext(p) == ".xml" && !isContentXML(*header, body) {
+		return &decodeError{format: "xml", err: fmt.Errorf("Response's content is not xml")}
+	}
+	return decodePrtgResponse(body, header, v)
+}
+
+func (c *Client) getSensorDetail(q *url.Values) (*prtgSensorDetailsResponse, error) {
+	p := GetSensorDetailsEndpoint
 
 	var sensorDetailResp prtgSensorDetailsResponse
-	err = getPrtgResponse(u, c.Timeout, &sensorDetailResp)
+	err := c.getPrtgResponse(p, q, &sensorDetailResp)
 	if err != nil {
 		return nil, err
 	}
@@ -135,39 +262,12 @@ func (c *Client) getSensorDetail(q *url.Values) (*prtgSensorDetailsResponse, err
 func (c *Client) getSensorDetailXML(q *url.Values) (*prtgSensorDetailsResponse, error) {
 	p := GetSensorDetailsEndpointXML
 
-	// Complete URL
-	u, err := c.getCompleteUrl(p, q)
-	if err != nil {
-		return nil, err
-	}
-
 	var sensorDetailRespXML prtgSensorDetailsResponseXML
-	err = getPrtgResponse(u, c.Timeout, &sensorDetailRespXML)
+	err := c.getPrtgResponse(p, q, &sensorDetailRespXML)
 	if err != nil {
 		return nil, err
 	}
-	sensorDetailRespXML.Name = trimWeirdCharacter(sensorDetailRespXML.Name)
-	sensorDetailRespXML.SensorType = trimWeirdCharacter(sensorDetailRespXML.SensorType)
-	sensorDetailRespXML.Interval = trimWeirdCharacter(sensorDetailRespXML.Interval)
-	sensorDetailRespXML.ProbeName = trimWeirdCharacter(sensorDetailRespXML.ProbeName)
-	sensorDetailRespXML.ParentGroupName = trimWeirdCharacter(sensorDetailRespXML.ParentGroupName)
-	sensorDetailRespXML.ParentDeviceName = trimWeirdCharacter(sensorDetailRespXML.ParentDeviceName)
-	sensorDetailRespXML.ParentDeviceId = trimWeirdCharacter(sensorDetailRespXML.ParentDeviceId)
-	sensorDetailRespXML.LastValue = trimWeirdCharacter(sensorDetailRespXML.LastValue)
-	sensorDetailRespXML.LastMessage = trimWeirdCharacter(sensorDetailRespXML.LastMessage)
-	sensorDetailRespXML.Favorite = trimWeirdCharacter(sensorDetailRespXML.Favorite)
-	sensorDetailRespXML.StatusText = trimWeirdCharacter(sensorDetailRespXML.StatusText)
-	sensorDetailRespXML.StatusId = trimWeirdCharacter(sensorDetailRespXML.StatusId)
-	sensorDetailRespXML.LastUp = trimWeirdCharacter(sensorDetailRespXML.LastUp)
-	sensorDetailRespXML.LastDown = trimWeirdCharacter(sensorDetailRespXML.LastDown)
-	sensorDetailRespXML.LastCheck = trimWeirdCharacter(sensorDetailRespXML.LastCheck)
-	sensorDetailRespXML.Uptime = trimWeirdCharacter(sensorDetailRespXML.Uptime)
-	sensorDetailRespXML.UptimeTime = trimWeirdCharacter(sensorDetailRespXML.UptimeTime)
-	sensorDetailRespXML.Downtime = trimWeirdCharacter(sensorDetailRespXML.Downtime)
-	sensorDetailRespXML.DowntimeTime = trimWeirdCharacter(sensorDetailRespXML.DowntimeTime)
-	sensorDetailRespXML.UpDownTotal = trimWeirdCharacter(sensorDetailRespXML.UpDownTotal)
-	sensorDetailRespXML.UpDownSince = trimWeirdCharacter(sensorDetailRespXML.UpDownSince)
-	sensorDetailRespXML.Info = trimWeirdCharacter(sensorDetailRespXML.Info)
+	trimStringFields(&sensorDetailRespXML.PrtgSensorData)
 	sensorDetailResp := prtgSensorDetailsResponse{
 		PrtgVersion: sensorDetailRespXML.PrtgVersion,
 		SensorData:  sensorDetailRespXML.PrtgSensorData,
@@ -181,34 +281,48 @@ func trimWeirdCharacter(str string) string {
 }
 
 // GetPrtgVersion returns PRTG's version of the specified server.
+// The response's format follows the client's Format.
 func (c *Client) GetPrtgVersion() (string, error) {
 	// Set the query
 	q := c.getTemplateUrlQuery()
 	q.Set("id", "0")
 
-	var sensorDetailResp *prtgSensorDetailsResponse
-	sensorDetailResp, err := c.getSensorDetail(q)
+	sensorDetailResp, err := c.requestSensorDetail(q)
 	if err != nil {
-		// Try XML
-		sensorDetailResp, err = c.getSensorDetailXML(q)
-		if err != nil {
-			return "", err
-		}
+		return "", err
 	}
 	return sensorDetailResp.PrtgVersion, nil
 }
 
+func (c *Client) requestSensorDetail(q *url.Values) (*prtgSensorDetailsResponse, error) {
+	var sensorDetailResp *prtgSensorDetailsResponse
+	requestDetail := func(getDetail func(*Client, *url.Values) (*prtgSensorDetailsResponse, error)) func(*Client) (string, error) {
+		return func(c *Client) (string, error) {
+			resp, err := getDetail(c, q)
+			if err != nil {
+				return "", err
+			}
+			sensorDetailResp = resp
+			return resp.PrtgVersion, nil
+		}
+	}
+	err := c.requestWithFormat(requestDetail((*Client).getSensorDetail), requestDetail((*Client).getSensorDetailXML))
+	if err != nil {
+		return nil, err
+	}
+	return sensorDetailResp, nil
+}
+
 // GetSensorDetail returns the detail of specified sensor.
+// The response's format follows the client's Format.
 func (c *Client) GetSensorDetail(id int64) (*PrtgSensorData, error) {
 	// Set the query
 	q := c.getTemplateUrlQuery()
 	q.Set("id", fmt.Sprintf("%v", id))
 
-	// Try JSON
-	sensorDetailResp, err := c.getSensorDetail(q)
+	sensorDetailResp, err := c.requestSensorDetail(q)
 	if err != nil {
-		// Try XML
-		return c.GetSensorDetailXML(id)
+		return nil, err
 	}
 	return &sensorDetailResp.SensorData, nil
 }
@@ -226,6 +340,16 @@ func (c *Client) GetSensorDetailXML(id int64) (*PrtgSensorData, error) {
 	return &sensorDetailResp.SensorData, nil
 }
 
+// GetSensorDetailTyped returns the detail of specified sensor with typed properties.
+// The response's format follows the client's Format.
+func (c *Client) GetSensorDetailTyped(id int64) (*PrtgSensorDataTyped, error) {
+	sensorDetail, err := c.GetSensorDetail(id)
+	if err != nil {
+		return nil, err
+	}
+	return sensorDetail.Typed()
+}
+
 func (c *Client) getHistoricData(id, average int64, startDate, endDate time.Time) (*prtgHistoricDataResponse, error) {
 	// Compose queries
 	q := c.getTemplateUrlQuery()
@@ -235,14 +359,8 @@ func (c *Client) getHistoricData(id, average int64, startDate, endDate time.Time
 	q.Set("eDate", fmt.Sprintf("%v", endDate.Format(dateFormat)))
 	q.Set("usecaption", fmt.Sprintf("%v", 1))
 	p := GetHistoricDatasEndpoint
-	// Complete URL
-	u, err := c.getCompleteUrl(p, q)
-	if err != nil {
-		return nil, err
-	}
-
 	var histDataResp prtgHistoricDataResponse
-	err = getPrtgResponse(u, c.Timeout, &histDataResp)
+	err := c.getPrtgResponse(p, q, &histDataResp)
 	if err != nil {
 		return nil, err
 	}
@@ -253,32 +371,104 @@ func getDeltaSecond(sDate, eDate time.Time) int64 {
 	return eDate.Unix() - sDate.Unix()
 }
 
-// GetHistoricData returns series of recorded data of specified sensor.
-// Take start and end of date's boundaries.
-func (c *Client) GetHistoricData(id, average int64, startDate, endDate time.Time) ([]PrtgHistoricData, error) {
-	// Validate Input
+func validateHistoricDataInput(id, average int64, startDate, endDate time.Time) error {
 	// Make sure that id and average is not less than 0
 	if id < 0 || average < 0 {
-		return nil, fmt.Errorf("Id and average should be more than or equals to zero")
+		return fmt.Errorf("Id and average should be more than or equals to zero")
 	}
 	// Make sure that data range less than 31 days
 	if deltaSecond := getDeltaSecond(startDate, endDate); (deltaSecond < 0) || (deltaSecond > deltaHistoricThreshold) {
-		return nil, fmt.Errorf("Data range is more than 31 days")
+		return fmt.Errorf("Data range is more than 31 days")
+	}
+	return nil
+}
+
+// GetHistoricData returns series of recorded data of specified sensor.
+// Take start and end of date's boundaries.
+// The response's format follows the client's Format.
+func (c *Client) GetHistoricData(id, average int64, startDate, endDate time.Time) ([]PrtgHistoricData, error) {
+	// Validate Input
+	if err := validateHistoricDataInput(id, average, startDate, endDate); err != nil {
+		return nil, err
 	}
 
 	// Get Historic Data using PRTG's API
-	// Try JSON
-	histDataResp, err := c.getHistoricData(id, average, startDate, endDate)
+	histData, err := c.requestHistoricData(id, average, startDate, endDate)
 	if err != nil {
-		// Try XML
-		return c.GetHistoricDataXML(id, average, startDate, endDate)
+		return nil, err
 	}
-	if len(histDataResp.HistoricData) <= 0 {
-		return histDataResp.HistoricData, fmt.Errorf("No Data Found")
+	if len(histData) <= 0 {
+		return histData, fmt.Errorf("No Data Found")
 	}
 
 	// Return the historic data
-	return histDataResp.HistoricData, nil
+	return histData, nil
+}
+
+// historicRangeChunk is the length of each request of GetHistoricDataRange, within the 31 days limit.
+const historicRangeChunk = 30 * 24 * time.Hour
+
+// GetHistoricDataRange returns series of recorded data of specified sensor like GetHistoricData,
+// but the period may be longer than 31 days, e.g. a quarter.
+// The period is split into requests within the limit, and their records are merged.
+func (c *Client) GetHistoricDataRange(id, average int64, startDate, endDate time.Time) ([]PrtgHistoricData, error) {
+	// Validate Input
+	if id < 0 || average < 0 {
+		return nil, fmt.Errorf("Id and average should be more than or equals to zero")
+	}
+	if !startDate.Before(endDate) {
+		return nil, fmt.Errorf("Start date should be before end date")
+	}
+
+	histData := []PrtgHistoricData{}
+	seen := map[string]bool{}
+	for chunkStart := startDate; chunkStart.Before(endDate); chunkStart = chunkStart.Add(historicRangeChunk) {
+		chunkEnd := chunkStart.Add(historicRangeChunk)
+		if chunkEnd.After(endDate) {
+			chunkEnd = endDate
+		}
+		chunk, err := c.requestHistoricData(id, average, chunkStart, chunkEnd)
+		if err != nil {
+			return nil, err
+		}
+		for _, data := range chunk {
+			// The record at the chunks' boundary is returned twice
+			datetime := fmt.Sprint(data["datetime"])
+			if seen[datetime] {
+				continue
+			}
+			seen[datetime] = true
+			histData = append(histData, data)
+		}
+	}
+	if len(histData) <= 0 {
+		return histData, fmt.Errorf("No Data Found")
+	}
+	return histData, nil
+}
+
+// requestHistoricData requests the historic data in the client's Format, without validating the input.
+func (c *Client) requestHistoricData(id, average int64, startDate, endDate time.Time) ([]PrtgHistoricData, error) {
+	var histData []PrtgHistoricData
+	err := c.requestWithFormat(func(c *Client) (string, error) {
+		histDataResp, err := c.getHistoricData(id, average, startDate, endDate)
+		if err != nil {
+			return "", err
+		}
+		histData = histDataResp.HistoricData
+		return histDataResp.PrtgVersion, nil
+	}, func(c *Client) (string, error) {
+		histDataRespXML, err := c.getHistoricDataXML(id, average, startDate, endDate)
+		if err != nil {
+			return "", err
+		}
+		histData = normalizeHistoricDataXML(histDataRespXML)
+		return histDataRespXML.PrtgVersion, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get historic data: %v", err)
+	}
+	return histData, nil
 }
 
 func (c *Client) getHistoricDataXML(id, average int64, startDate, endDate time.Time) (*prtgHistoricDataResponseXML, error) {
@@ -290,14 +480,8 @@ func (c *Client) getHistoricDataXML(id, average int64, startDate, endDate time.T
 	q.Set("eDate", fmt.Sprintf("%v", endDate.Format(dateFormat)))
 	q.Set("usecaption", fmt.Sprintf("%v", 1))
 	p := GetHistoricDatasEndpointXML
-	// Complete URL
-	u, err := c.getCompleteUrl(p, q)
-	if err != nil {
-		return nil, err
-	}
-
 	var histDataRespXML prtgHistoricDataResponseXML
-	err = getPrtgResponse(u, c.Timeout, &histDataRespXML)
+	err := c.getPrtgResponse(p, q, &histDataRespXML)
 	if err != nil {
 		return nil, err
 	}
@@ -316,7 +500,43 @@ func (c *Client) GetHistoricDataXML(id, average int64, startDate, endDate time.T
 		return nil, fmt.Errorf("No Data Found")
 	}
 
-	// Normalize data as map[string]interface{}
+	// Return the historic data
+	return normalizeHistoricDataXML(histDataRespXML), nil
+}
+
+// GetHistoricDataCSV writes series of recorded data of specified sensor into w,
+// as returned by PRTG's CSV endpoint.
+// Take start and end of date's boundaries.
+func (c *Client) GetHistoricDataCSV(w io.Writer, id, average int64, startDate, endDate time.Time) error {
+	// Validate Input
+	if err := validateHistoricDataInput(id, average, startDate, endDate); err != nil {
+		return err
+	}
+
+	// Compose queries
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("avg", fmt.Sprintf("%v", average))
+	q.Set("sDate", fmt.Sprintf("%v", startDate.Format(dateFormat)))
+	q.Set("eDate", fmt.Sprintf("%v", endDate.Format(dateFormat)))
+	q.Set("usecaption", fmt.Sprintf("%v", 1))
+	p := GetHistoricDatasEndpointCSV
+	body, _, err := c.getResponseBody(p, q)
+	if err != nil {
+		return fmt.Errorf("Unable to get historic data: %v", err)
+	}
+	// PRTG reports the error in XML or JSON
+	if sniffContent(body) != "" {
+		return fmt.Errorf("Unable to get historic data: Response's content is not csv")
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("Unable to write historic data: %v", err)
+	}
+	return nil
+}
+
+// normalizeHistoricDataXML converts XML's items as map[string]interface{}
+func normalizeHistoricDataXML(histDataRespXML *prtgHistoricDataResponseXML) []PrtgHistoricData {
 	histData := []PrtgHistoricData{}
 	for _, data := range histDataRespXML.HistoricData {
 		tempHistData := PrtgHistoricData{}
@@ -330,30 +550,29 @@ func (c *Client) GetHistoricDataXML(id, average int64, startDate, endDate time.T
 		}
 		histData = append(histData, tempHistData)
 	}
-	histDataResp := prtgHistoricDataResponse{
-		HistoricData: histData,
-	}
-
-	// Return the historic data
-	return histDataResp.HistoricData, nil
+	return histData
 }
 
 func (c *Client) getTableList(id int64, content string, columns []string) (*prtgTableListResponse, error) {
+	return c.getFilteredTableList(id, content, columns, nil)
+}
+
+// getFilteredTableList requests the table list with additional queries, e.g. filter_dstart.
+func (c *Client) getFilteredTableList(id int64, content string, columns []string, filters url.Values) (*prtgTableListResponse, error) {
 	// Compose queries
 	q := c.getTemplateUrlQuery()
 	q.Set("id", fmt.Sprintf("%v", id))
 	q.Set("content", fmt.Sprintf("%v", content))
 	colStr := strings.Join(columns, ",")
 	q.Set("columns", fmt.Sprintf("%v", colStr))
-	p := GetTableListsEndpoint
-	// Complete URL
-	u, err := c.getCompleteUrl(p, q)
-	if err != nil {
-		return nil, err
+	for key, values := range filters {
+		for _, value := range values {
+			q.Add(key, value)
+		}
 	}
-
+	p := GetTableListsEndpoint
 	var tableListResp prtgTableListResponse
-	err = getPrtgResponse(u, c.Timeout, &tableListResp)
+	err := c.getPrtgResponse(p, q, &tableListResp)
 	if err != nil {
 		return nil, err
 	}
@@ -445,20 +664,41 @@ func (c *Client) GetGroupList(id int64, columns []string) ([]PrtgTableList, erro
 	return sensorListResp.Groups, nil
 }
 
+// GetChannelList returns list of channel within specified sensor.
+// The default columns's value is nil.
+func (c *Client) GetChannelList(id int64, columns []string) ([]PrtgChannelList, error) {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return nil, fmt.Errorf("Id should be more than or equals to zero")
+	}
+	// if columns is nil, use the default column's entry instead
+	if columns == nil {
+		columns = defaultChannelListCols
+	}
+
+	// Get channel list within this sensor
+	content := "channels"
+	channelListResp, err := c.getTableList(id, content, columns)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get channel list data: %v", err)
+	}
+	if len(channelListResp.Channels) <= 0 {
+		return channelListResp.Channels, fmt.Errorf("No Data Found")
+	}
+
+	// Return channel list
+	return channelListResp.Channels, nil
+}
+
 func (c *Client) getTableTree(id int64) (*PrtgSensorTreeResponse, error) {
 	// Compose queries
 	q := c.getTemplateUrlQuery()
 	q.Set("id", fmt.Sprintf("%v", id))
 	q.Set("content", "sensortree")
 	p := GetSensorTreesEndpoint
-	// Complete URL
-	u, err := c.getCompleteUrl(p, q)
-	if err != nil {
-		return nil, err
-	}
-
 	var tableTreeResp PrtgSensorTreeResponse
-	err = getPrtgResponse(u, c.Timeout, &tableTreeResp)
+	err := c.getPrtgResponse(p, q, &tableTreeResp)
 	if err != nil {
 		return nil, err
 	}
@@ -415,6 +415,51 @@ func TestHistDataXML(t *testing.T) {
 	}
 }
 
+func TestGetHistoricDataRange(t *testing.T) {
+	requests := 0
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetHistoricDatasEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		sDate, _ := time.Parse(dateFormat, r.FormValue("sDate"))
+		eDate, _ := time.Parse(dateFormat, r.FormValue("eDate"))
+		if getDeltaSecond(sDate, eDate) > deltaHistoricThreshold {
+			fmt.Fprint(w, `{"prtg-version":"18.2.41.1636","treesize":0,"histdata":[]}`)
+			return
+		}
+		// A record at both boundaries of the requested period
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"prtg-version":"18.2.41.1636","treesize":2,"histdata":[
+			{"datetime":"%v","Traffic In":1,"coverage":"100 %%"},{"datetime":"%v","Traffic In":2,"coverage":"100 %%"}]}`,
+			sDate.Format(prtgHistoricDateFormat), eDate.Format(prtgHistoricDateFormat))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	startDate := time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 0, 65)
+	histData, err := client.GetHistoricDataRange(9321, 300, startDate, endDate)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	// The period is split into 3 requests, and the records at their boundaries are merged
+	if requests != 3 || len(histData) != 4 {
+		t.Errorf("There should be 3 requests and 4 records instead of %v and %v", requests, len(histData))
+	}
+	if last := histData[3]["datetime"]; last != endDate.Format(prtgHistoricDateFormat) {
+		t.Errorf("Last record is at %v instead of %v", last, endDate)
+	}
+
+	// Invalid input
+	if _, err := client.GetHistoricDataRange(-1, 300, startDate, endDate); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+	if _, err := client.GetHistoricDataRange(9321, 300, endDate, startDate); err == nil {
+		t.Errorf("Since the start date is after the end date, an error should occur.")
+	}
+}
+
 func TestGetSensorList(t *testing.T) {
 	mux := new(http.ServeMux)
 	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
@@ -450,6 +495,9 @@ func TestGetSensorList(t *testing.T) {
 	}
 	if len(sensorList) <= 0 {
 		t.Errorf("It should be not empty.")
+	} else if sensorList[0].StatusRaw != 7 || sensorList[0].Message != "Paused by parent" {
+		t.Errorf("Sensor's status and message are %v and %v instead of 7 and Paused by parent",
+			sensorList[0].StatusRaw, sensorList[0].Message)
 	}
 
 	// Check sensor list within id 9000 (empty)
@@ -634,6 +682,57 @@ func TestGetGroupList(t *testing.T) {
 	}
 }
 
+func TestGetChannelList(t *testing.T) {
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		sensorId := r.FormValue("id")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.FormValue("content") != "channels" {
+			return
+		}
+		if sensorId == "9201" {
+			fmt.Fprint(w, loadfixture("/prtg_channel-list_9201.json"))
+		} else if sensorId == "9000" {
+			fmt.Fprint(w, loadfixture("/prtg_channel-list_9000_empty.json"))
+		}
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	client := NewClient(fmt.Sprintf("%v", serverURL), "user", "pass")
+
+	// Check channel list within id 9201
+	channelList, err := client.GetChannelList(9201, nil)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if len(channelList) != 3 {
+		t.Errorf("There should be 3 channels instead of %v", len(channelList))
+		return
+	}
+	if _, ok := channelList[0].RawValue(); ok {
+		t.Errorf("Channel %v should have no value", channelList[0].Name)
+	}
+	if value, ok := channelList[1].RawValue(); !ok || value != 128000 {
+		t.Errorf("Channel %v's value is %v instead of 128000", channelList[1].Name, value)
+	}
+
+	// Check channel list within id 9000 (empty)
+	channelList, err = client.GetChannelList(9000, nil)
+	if err == nil || len(channelList) > 0 {
+		t.Errorf("It should be empty.")
+	}
+
+	// Check sensor id less than zero
+	channelList, err = client.GetChannelList(-1, nil)
+	if err == nil {
+		t.Errorf("Since the id is less than zero, an error should occur.")
+	}
+}
+
 func responsesXmlOk(w http.ResponseWriter, content string) {
 	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
 	w.Header().Set("Content-Disposition", "attachment; filename=table.xml")
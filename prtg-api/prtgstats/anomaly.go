@@ -0,0 +1,218 @@
+package prtgstats
+
+import (
+	"math"
+	"time"
+)
+
+// defaultThreshold is the deviation from the baseline in standard deviations
+// above which a point is anomalous
+const defaultThreshold = 3
+
+// Anomaly is a point which deviates from the baseline of its series.
+type Anomaly struct {
+	Point
+	// Expected is the baseline's mean at the point
+	Expected float64
+	// Score is the deviation from the baseline in standard deviations, negative below it.
+	// It's infinite if the baseline doesn't vary at all.
+	Score float64
+}
+
+// Detector flags the anomalous points of a series.
+// The anomalies are sorted by time.
+type Detector interface {
+	Detect(s Series) []Anomaly
+}
+
+// Detect runs the detector over each series, and returns the anomalies per channel.
+// The channels without anomaly are left out.
+func Detect(series []Series, detector Detector) map[string][]Anomaly {
+	result := map[string][]Anomaly{}
+	for _, s := range series {
+		if anomalies := detector.Detect(s); len(anomalies) > 0 {
+			result[s.Channel] = anomalies
+		}
+	}
+	return result
+}
+
+// RollingZScore flags the points which deviate from the mean of the preceding points.
+type RollingZScore struct {
+	// Window is the number of preceding points of the baseline, 12 by default
+	Window int
+	// Threshold in standard deviations, 3 by default
+	Threshold float64
+}
+
+// Detect returns the points deviating from the preceding window.
+// The points before the window is full aren't checked.
+func (d RollingZScore) Detect(s Series) []Anomaly {
+	window := d.Window
+	if window <= 0 {
+		window = 12
+	}
+	anomalies := []Anomaly{}
+	var sum, sumSquares float64
+	for i, point := range s.Points {
+		if i >= window {
+			n := float64(window)
+			mean := sum / n
+			stdDev := math.Sqrt(math.Max(0, sumSquares/n-mean*mean))
+			if anomaly, ok := check(point, mean, stdDev, d.Threshold); ok {
+				anomalies = append(anomalies, anomaly)
+			}
+			old := s.Points[i-window].Value
+			sum -= old
+			sumSquares -= old * old
+		}
+		sum += point.Value
+		sumSquares += point.Value * point.Value
+	}
+	return anomalies
+}
+
+// EWMA flags the points which deviate from the exponentially weighted moving average,
+// so the baseline follows slow changes while the recent points weigh the most.
+type EWMA struct {
+	// Alpha is the weight of the newest point between 0 and 1, 0.1 by default
+	Alpha float64
+	// Warmup is the number of first points which only build the baseline, 10 by default
+	Warmup int
+	// Threshold in standard deviations, 3 by default
+	Threshold float64
+}
+
+// Detect returns the points deviating from the moving average of the preceding points.
+func (d EWMA) Detect(s Series) []Anomaly {
+	alpha, warmup := d.Alpha, d.Warmup
+	if alpha <= 0 || alpha > 1 {
+		alpha = 0.1
+	}
+	if warmup <= 0 {
+		warmup = 10
+	}
+	anomalies := []Anomaly{}
+	var mean, variance float64
+	for i, point := range s.Points {
+		if i == 0 {
+			mean = point.Value
+			continue
+		}
+		if i >= warmup {
+			if anomaly, ok := check(point, mean, math.Sqrt(variance), d.Threshold); ok {
+				anomalies = append(anomalies, anomaly)
+			}
+		}
+		diff := point.Value - mean
+		increment := alpha * diff
+		mean += increment
+		variance = (1 - alpha) * (variance + diff*increment)
+	}
+	return anomalies
+}
+
+// HourOfWeek flags the points which deviate from the same hour of the week in the other weeks,
+// e.g. traffic on Monday at 9 AM is compared with the other Mondays at 9 AM,
+// so the daily and weekly patterns aren't anomalous.
+type HourOfWeek struct {
+	// MinWeeks is the number of other weeks the baseline needs, 2 by default.
+	// The points without enough weeks aren't checked.
+	MinWeeks int
+	// Location of the hours, UTC by default
+	Location *time.Location
+	// Threshold in standard deviations, 3 by default
+	Threshold float64
+}
+
+// hourlyStats accumulates the values of an hour of the week.
+type hourlyStats struct {
+	count      float64
+	sum        float64
+	sumSquares float64
+}
+
+func (h *hourlyStats) add(value float64) {
+	h.count++
+	h.sum += value
+	h.sumSquares += value * value
+}
+
+// Detect returns the points deviating from the same hour of the other weeks within the series.
+func (d HourOfWeek) Detect(s Series) []Anomaly {
+	minWeeks, loc := d.MinWeeks, d.Location
+	if minWeeks <= 0 {
+		minWeeks = 2
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// The totals of each hour of the week, and its share of each week
+	totals := map[int]*hourlyStats{}
+	weekly := map[[2]int]*hourlyStats{}
+	hours := make([]int, len(s.Points))
+	weeks := make([]int, len(s.Points))
+	for i, point := range s.Points {
+		hours[i], weeks[i] = hourOfWeek(point.Time.In(loc))
+		if totals[hours[i]] == nil {
+			totals[hours[i]] = &hourlyStats{}
+		}
+		key := [2]int{hours[i], weeks[i]}
+		if weekly[key] == nil {
+			weekly[key] = &hourlyStats{}
+		}
+		totals[hours[i]].add(point.Value)
+		weekly[key].add(point.Value)
+	}
+	weeksPerHour := map[int]int{}
+	for key := range weekly {
+		weeksPerHour[key[0]]++
+	}
+
+	anomalies := []Anomaly{}
+	for i, point := range s.Points {
+		if weeksPerHour[hours[i]]-1 < minWeeks {
+			continue
+		}
+		total, own := totals[hours[i]], weekly[[2]int{hours[i], weeks[i]}]
+		n := total.count - own.count
+		mean := (total.sum - own.sum) / n
+		stdDev := math.Sqrt(math.Max(0, (total.sumSquares-own.sumSquares)/n-mean*mean))
+		if anomaly, ok := check(point, mean, stdDev, d.Threshold); ok {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	return anomalies
+}
+
+// hourOfWeek returns the hour since Monday midnight, and the number of the week starting on Monday.
+func hourOfWeek(t time.Time) (int, int) {
+	weekday := (int(t.Weekday()) + 6) % 7
+	year, month, day := t.Date()
+	// The days since 1970-01-01, which is a Thursday
+	days := int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+	return weekday*24 + t.Hour(), int(math.Floor(float64(days+3) / 7))
+}
+
+// check returns the point as an anomaly if it deviates from the mean by more than the threshold.
+func check(point Point, mean, stdDev, threshold float64) (Anomaly, bool) {
+	if threshold <= 0 {
+		threshold = defaultThreshold
+	}
+	diff := point.Value - mean
+	if diff == 0 {
+		return Anomaly{}, false
+	}
+	score := math.Inf(1)
+	if diff < 0 {
+		score = math.Inf(-1)
+	}
+	if stdDev > 0 {
+		score = diff / stdDev
+	}
+	if math.Abs(score) <= threshold {
+		return Anomaly{}, false
+	}
+	return Anomaly{Point: point, Expected: mean, Score: score}, true
+}
@@ -0,0 +1,101 @@
+package prtgstats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+// getNoisySeries returns a series every 5 minutes alternating between 9 and 11,
+// with a spike of 30 at the 30th point.
+func getNoisySeries() Series {
+	s := Series{Channel: "Ping Time", Interval: 5 * time.Minute}
+	for i := 0; i < 48; i++ {
+		value := 9.0 + float64(i%2)*2
+		if i == 30 {
+			value = 30
+		}
+		s.Points = append(s.Points, Point{Time: testStart.Add(time.Duration(i) * 5 * time.Minute), Value: value, Coverage: 100})
+	}
+	return s
+}
+
+func TestRollingZScoreAndEWMA(t *testing.T) {
+	spike := testStart.Add(150 * time.Minute)
+	for _, detector := range []Detector{RollingZScore{}, EWMA{}} {
+		anomalies := detector.Detect(getNoisySeries())
+		if len(anomalies) != 1 || !anomalies[0].Time.Equal(spike) || anomalies[0].Value != 30 || anomalies[0].Score <= 3 {
+			t.Errorf("%T should only flag the spike instead of %+v", detector, anomalies)
+		}
+	}
+
+	// The threshold is configurable
+	if anomalies := (RollingZScore{Threshold: 100}).Detect(getNoisySeries()); len(anomalies) != 0 {
+		t.Errorf("The spike is below the threshold, but flagged: %+v", anomalies)
+	}
+	if anomalies := (EWMA{Warmup: 40}).Detect(getNoisySeries()); len(anomalies) != 0 {
+		t.Errorf("The spike is within the warmup, but flagged: %+v", anomalies)
+	}
+
+	// A change of a constant value is an infinite deviation
+	constant := Series{Channel: "Packet Loss"}
+	for i := 0; i < 20; i++ {
+		constant.Points = append(constant.Points, Point{Time: testStart.Add(time.Duration(i) * time.Minute)})
+	}
+	constant.Points[15].Value = 100
+	anomalies := RollingZScore{Window: 5}.Detect(constant)
+	if len(anomalies) != 1 || !math.IsInf(anomalies[0].Score, 1) || anomalies[0].Expected != 0 {
+		t.Errorf("Unexpected anomalies of the constant series: %+v", anomalies)
+	}
+}
+
+func TestHourOfWeek(t *testing.T) {
+	// Hourly values of 4 weeks following the hour of the day, with a spike on the third Wednesday at 10 AM.
+	// 2019-12-02 is a Monday.
+	start := time.Date(2019, time.December, 2, 0, 0, 0, 0, time.UTC)
+	spike := start.AddDate(0, 0, 16).Add(10 * time.Hour)
+	s := Series{Channel: "Traffic In", Interval: time.Hour}
+	for i := 0; i < 4*7*24; i++ {
+		point := Point{Time: start.Add(time.Duration(i) * time.Hour), Coverage: 100}
+		point.Value = float64(point.Time.Hour()*10 + (i/168)%2)
+		if point.Time.Equal(spike) {
+			point.Value = 500
+		}
+		s.Points = append(s.Points, point)
+	}
+
+	anomalies := HourOfWeek{}.Detect(s)
+	if len(anomalies) != 1 || !anomalies[0].Time.Equal(spike) || math.Abs(anomalies[0].Expected-100.6667) > 0.0001 {
+		t.Errorf("Only the spike should be flagged instead of %+v", anomalies)
+	}
+	// The daily pattern is anomalous to a detector without seasonality
+	if anomalies := (RollingZScore{}).Detect(s); len(anomalies) <= 1 {
+		t.Errorf("The rolling z-score should flag the daily pattern too, instead of %+v", anomalies)
+	}
+	// Without enough weeks, nothing is checked
+	if anomalies := (HourOfWeek{MinWeeks: 4}).Detect(s); len(anomalies) != 0 {
+		t.Errorf("There are only 3 other weeks, but flagged: %+v", anomalies)
+	}
+	// The hours are in the location, so the spike is on another hour of the week
+	loc := time.FixedZone("UTC+7", 7*3600)
+	if anomalies := (HourOfWeek{Location: loc}).Detect(s); len(anomalies) != 1 || !anomalies[0].Time.Equal(spike) {
+		t.Errorf("Only the spike should be flagged in %v instead of %+v", loc, anomalies)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	for _, format := range []prtg.ResponseFormat{prtg.FormatJSON, prtg.FormatXML} {
+		series := getTestSeries(t, format)
+		// The ping time rises steadily after the gap, and the packet loss stays at zero
+		anomalies := Detect(series, RollingZScore{Window: 4})
+		if len(anomalies) != 1 || len(anomalies["Ping Time"]) != 1 {
+			t.Errorf("Only the ping time after the gap should be flagged instead of %+v", anomalies)
+			continue
+		}
+		if flagged := anomalies["Ping Time"][0]; !flagged.Time.Equal(testStart.Add(time.Hour)) || flagged.Coverage != 50 {
+			t.Errorf("Unexpected anomaly: %+v", flagged)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package prtgstats
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+// BillingOptions configures Bill. The zero value bills SNMP Traffic sensors on the 95th percentile
+// of 5-minute raw values.
+type BillingOptions struct {
+	// InChannel and OutChannel are the traffic channels, "Traffic In" and "Traffic Out" by default.
+	// PRTG's historic data may name them with " (speed)" suffix, which is found too.
+	InChannel  string
+	OutChannel string
+	// Percentile to bill, 95 by default
+	Percentile float64
+	// Average is the sample interval, 5 minutes by default
+	Average time.Duration
+	// Formatted uses the formatted values instead of the raw values
+	Formatted bool
+	// BitsPerUnit converts the values into bits per second, 8 by default since the raw traffic is in bytes per second
+	BitsPerUnit float64
+}
+
+func (opts *BillingOptions) withDefaults() BillingOptions {
+	result := BillingOptions{}
+	if opts != nil {
+		result = *opts
+	}
+	if result.InChannel == "" {
+		result.InChannel = "Traffic In"
+	}
+	if result.OutChannel == "" {
+		result.OutChannel = "Traffic Out"
+	}
+	if result.Percentile == 0 {
+		result.Percentile = 95
+	}
+	if result.Average == 0 {
+		result.Average = 5 * time.Minute
+	}
+	if result.BitsPerUnit == 0 {
+		result.BitsPerUnit = 8
+	}
+	return result
+}
+
+// Invoice is the billing record of a traffic sensor. The rates are in bits per second.
+type Invoice struct {
+	SensorID int64
+	Start    time.Time
+	End      time.Time
+	// Percentile billed, e.g. 95
+	Percentile float64
+	In         float64
+	Out        float64
+	// Combined is the percentile of the sum of both directions per sample
+	Combined float64
+	// Billable is the greater of In and Out, as burstable billing commonly charges
+	Billable float64
+	PeakIn   float64
+	PeakOut  float64
+	// Samples is the number of samples with both directions, out of the Expected within the period
+	Samples  int
+	Expected int
+}
+
+// MonthPeriod returns the start and end of the month in the location, e.g. for a billing month.
+func MonthPeriod(year int, month time.Month, loc *time.Location) (time.Time, time.Time) {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// Bill returns an invoice for each traffic sensor between the start and end of date's boundaries,
+// which may be longer than PRTG's 31 days limit.
+// The default options are used if opts is nil.
+func Bill(client *prtg.Client, ids []int64, startDate, endDate time.Time, opts *BillingOptions) ([]Invoice, error) {
+	options := opts.withDefaults()
+	if options.Average < time.Second {
+		return nil, fmt.Errorf("Average should be at least a second")
+	}
+	invoices := make([]Invoice, 0, len(ids))
+	for _, id := range ids {
+		histData, err := client.GetHistoricDataRange(id, int64(options.Average/time.Second), startDate, endDate)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to bill sensor %v: %v", id, err)
+		}
+		invoice, err := BillHistoricData(histData, startDate, endDate, &options)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to bill sensor %v: %v", id, err)
+		}
+		invoice.SensorID = id
+		invoices = append(invoices, *invoice)
+	}
+	return invoices, nil
+}
+
+// BillHistoricData returns the invoice of the traffic sensor's historic data within the period.
+// The records outside of the period, or without both directions, are not billed.
+func BillHistoricData(histData []prtg.PrtgHistoricData, startDate, endDate time.Time, opts *BillingOptions) (*Invoice, error) {
+	options := opts.withDefaults()
+	series, err := FromHistoricData(histData, !options.Formatted)
+	if err != nil {
+		return nil, err
+	}
+	in, err := findTraffic(series, options.InChannel)
+	if err != nil {
+		return nil, err
+	}
+	out, err := findTraffic(series, options.OutChannel)
+	if err != nil {
+		return nil, err
+	}
+
+	outByTime := map[int64]float64{}
+	for _, point := range out.Points {
+		outByTime[point.Time.UnixNano()] = point.Value
+	}
+	var inRates, outRates, combinedRates []float64
+	for _, point := range in.Points {
+		outValue, ok := outByTime[point.Time.UnixNano()]
+		if !ok || point.Time.Before(startDate) || !point.Time.Before(endDate) {
+			continue
+		}
+		inRate, outRate := point.Value*options.BitsPerUnit, outValue*options.BitsPerUnit
+		inRates = append(inRates, inRate)
+		outRates = append(outRates, outRate)
+		combinedRates = append(combinedRates, inRate+outRate)
+	}
+
+	invoice := &Invoice{
+		Start:      startDate,
+		End:        endDate,
+		Percentile: options.Percentile,
+		In:         Percentile(inRates, options.Percentile),
+		Out:        Percentile(outRates, options.Percentile),
+		Combined:   Percentile(combinedRates, options.Percentile),
+		PeakIn:     Percentile(inRates, 100),
+		PeakOut:    Percentile(outRates, 100),
+		Samples:    len(inRates),
+		Expected:   int(math.Ceil(float64(endDate.Sub(startDate)) / float64(options.Average))),
+	}
+	invoice.Billable = math.Max(invoice.In, invoice.Out)
+	return invoice, nil
+}
+
+// findTraffic returns the channel's series, or its " (speed)" series, which has values.
+func findTraffic(series []Series, channel string) (Series, error) {
+	for _, name := range []string{channel, channel + " (speed)"} {
+		if s, ok := Find(series, name); ok && len(s.Points) > 0 {
+			return s, nil
+		}
+	}
+	return Series{}, fmt.Errorf("Historic data has no value of %v channel", channel)
+}
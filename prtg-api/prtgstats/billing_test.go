@@ -0,0 +1,80 @@
+package prtgstats
+
+import (
+	"testing"
+	"time"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api/prtgtest"
+)
+
+func TestBill(t *testing.T) {
+	s := prtgtest.NewServer()
+	defer s.Close()
+	device := s.AddDevice(prtgtest.RootID, "Customer A", "10.0.0.1")
+	traffic := s.AddSensor(device, prtgtest.Sensor{Name: "Uplink", Type: "snmptraffic"})
+	ping := s.AddSensor(device, prtgtest.Sensor{Name: "Ping", Type: "ping"})
+
+	// 35 days of 5-minute samples in kbit/s, bursting to 50 Mbit/s in 4% of the samples
+	startDate := time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 0, 35)
+	records := []prtgtest.HistoricRecord{}
+	for i := 0; startDate.Add(time.Duration(i) * 5 * time.Minute).Before(endDate); i++ {
+		in := 1000.0
+		if i%25 == 0 {
+			in = 50000
+		}
+		records = append(records, prtgtest.HistoricRecord{
+			Time:     startDate.Add(time.Duration(i) * 5 * time.Minute),
+			Coverage: 100,
+			Values:   map[string]float64{"Traffic In (speed)": in, "Traffic Out (speed)": 500},
+		})
+	}
+	s.AddHistoricData(traffic, records...)
+	s.AddHistoricData(ping, prtgtest.HistoricRecord{Time: startDate, Coverage: 100, Values: map[string]float64{"Ping Time": 12}})
+
+	client := prtg.NewClient(s.URL, prtgtest.DefaultUsername, prtgtest.DefaultPassword)
+	opts := &BillingOptions{Formatted: true, BitsPerUnit: 1000}
+	invoices, err := Bill(client, []int64{traffic}, startDate, endDate, opts)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if len(invoices) != 1 {
+		t.Errorf("There should be 1 invoice instead of %v", len(invoices))
+		return
+	}
+	// The bursts are within the top 5%, so they're not billed
+	invoice := invoices[0]
+	if invoice.SensorID != traffic || invoice.Percentile != 95 || invoice.In != 1e6 || invoice.Out != 5e5 ||
+		invoice.Combined != 1.5e6 || invoice.Billable != 1e6 {
+		t.Errorf("Unexpected invoice: %+v", invoice)
+	}
+	if invoice.PeakIn != 5e7 || invoice.PeakOut != 5e5 || invoice.Samples != 10080 || invoice.Expected != 10080 {
+		t.Errorf("Unexpected peak and samples: %+v", invoice)
+	}
+
+	// The 99th percentile includes the bursts
+	invoices, err = Bill(client, []int64{traffic}, startDate, endDate, &BillingOptions{Formatted: true, BitsPerUnit: 1000, Percentile: 99})
+	if err != nil || invoices[0].In != 5e7 {
+		t.Errorf("Unexpected 99th percentile invoice: %+v, error: %v", invoices, err)
+	}
+
+	// Invalid input
+	if _, err := Bill(client, []int64{traffic}, startDate, endDate, nil); err == nil {
+		t.Errorf("Since the historic data has no raw value, an error should occur.")
+	}
+	if _, err := Bill(client, []int64{ping}, startDate, endDate, opts); err == nil {
+		t.Errorf("Since the sensor has no traffic channel, an error should occur.")
+	}
+	if _, err := Bill(client, []int64{traffic}, startDate, endDate, &BillingOptions{Average: time.Millisecond}); err == nil {
+		t.Errorf("Since the average is less than a second, an error should occur.")
+	}
+}
+
+func TestMonthPeriod(t *testing.T) {
+	start, end := MonthPeriod(2020, time.February, time.UTC)
+	if !start.Equal(time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC)) || end.Sub(start) != 29*24*time.Hour {
+		t.Errorf("Unexpected period: %v - %v", start, end)
+	}
+}
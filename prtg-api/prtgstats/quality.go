@@ -0,0 +1,210 @@
+package prtgstats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+const (
+	// downtimeChannel is the channel PRTG adds to the historic data, the percentage of the record the sensor was down
+	downtimeChannel = "Downtime"
+	// gapTolerance is the multiple of the interval between records which is still not a gap,
+	// since the scans don't happen exactly on the interval
+	gapTolerance = 1.5
+)
+
+// QualityOptions configures AnalyzeQuality.
+type QualityOptions struct {
+	// Interval is the sensor's scanning interval.
+	// If it's zero, the interval is estimated from the records.
+	Interval time.Duration
+	// LowCoverage is the coverage in percent below which a record is poorly covered, 90 by default
+	LowCoverage float64
+}
+
+// Window is a period of consecutive records.
+type Window struct {
+	Start time.Time
+	End   time.Time
+	// Records within the window
+	Records int
+	// Coverage is the records' mean coverage in percent
+	Coverage float64
+}
+
+// Quality contains the data quality of a sensor's historic data within a period.
+//
+// A real outage is monitored, so its records are covered and report downtime.
+// A collection problem of the probe leaves gaps or poorly covered records instead.
+type Quality struct {
+	SensorID int64
+	Start    time.Time
+	End      time.Time
+	Interval time.Duration
+	// Records found, out of the Expected within the period.
+	// A partial interval at the end of the period expects a record too, like Invoice.Expected.
+	Records  int
+	Expected int
+	// Gaps are the periods of missing records
+	Gaps []Gap
+	// LowCoverage are the records covered less than the threshold
+	LowCoverage []Window
+	// Outages are the records reporting downtime
+	Outages []Window
+	// Completeness is the percentage of the expected records which are found
+	Completeness float64
+	// Score is the percentage of the period covered by monitoring data,
+	// both missing and poorly covered records lower it
+	Score float64
+}
+
+// qualityRecord is a record's time, coverage, and downtime.
+type qualityRecord struct {
+	time     time.Time
+	coverage float64
+	down     bool
+}
+
+// AnalyzeQuality returns the quality of the historic data between the start and end of date's boundaries.
+// The records outside of the period are ignored. The default options are used if opts is nil.
+func AnalyzeQuality(histData []prtg.PrtgHistoricData, startDate, endDate time.Time, opts *QualityOptions) (*Quality, error) {
+	options := QualityOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.LowCoverage == 0 {
+		options.LowCoverage = 90
+	}
+	if !startDate.Before(endDate) {
+		return nil, fmt.Errorf("Start date should be before end date")
+	}
+
+	records := make([]qualityRecord, 0, len(histData))
+	times := make([]time.Time, 0, len(histData))
+	for _, data := range histData {
+		t, err := data.Time()
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+		if t.Before(startDate) || !t.Before(endDate) {
+			continue
+		}
+		coverage, err := data.Coverage()
+		if err != nil {
+			return nil, err
+		}
+		downtime, ok := data.Value(downtimeChannel)
+		if !ok {
+			downtime, _ = data.RawValue(downtimeChannel)
+		}
+		records = append(records, qualityRecord{time: t, coverage: math.Max(0, math.Min(coverage, 100)), down: downtime > 0})
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].time.Before(records[j].time) })
+	if options.Interval == 0 {
+		options.Interval = estimateInterval(times)
+	}
+	if err := validateInterval(options.Interval); err != nil {
+		return nil, err
+	}
+
+	quality := &Quality{
+		Start:       startDate,
+		End:         endDate,
+		Interval:    options.Interval,
+		Records:     len(records),
+		Expected:    int(math.Ceil(float64(endDate.Sub(startDate)) / float64(options.Interval))),
+		Gaps:        findGaps(records, startDate, endDate, options.Interval),
+		LowCoverage: findWindows(records, options.Interval, func(r qualityRecord) bool { return r.coverage < options.LowCoverage }),
+		Outages:     findWindows(records, options.Interval, func(r qualityRecord) bool { return r.down }),
+	}
+	if quality.Expected > 0 {
+		quality.Completeness = math.Min(100, 100*float64(quality.Records)/float64(quality.Expected))
+	}
+	var covered float64
+	for _, record := range records {
+		covered += options.Interval.Seconds() * record.coverage / 100
+	}
+	quality.Score = math.Min(100, 100*covered/endDate.Sub(startDate).Seconds())
+	return quality, nil
+}
+
+// AnalyzeSensors returns the quality of each sensor's raw historic data between the start and end of date's boundaries,
+// based on the sensor's scanning interval. The options' interval is ignored.
+func AnalyzeSensors(client *prtg.Client, ids []int64, startDate, endDate time.Time, opts *QualityOptions) ([]Quality, error) {
+	options := QualityOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	qualities := make([]Quality, 0, len(ids))
+	for _, id := range ids {
+		detail, err := client.GetSensorDetailTyped(id)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to analyze sensor %v: %v", id, err)
+		}
+		histData, err := client.GetHistoricDataRange(id, 0, startDate, endDate)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to analyze sensor %v: %v", id, err)
+		}
+		options.Interval = detail.Interval
+		quality, err := AnalyzeQuality(histData, startDate, endDate, &options)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to analyze sensor %v: %v", id, err)
+		}
+		quality.SensorID = id
+		qualities = append(qualities, *quality)
+	}
+	return qualities, nil
+}
+
+// findGaps returns the periods in which a record is expected but missing,
+// including at the start and end of the period.
+func findGaps(records []qualityRecord, startDate, endDate time.Time, interval time.Duration) []Gap {
+	gaps := []Gap{}
+	tolerance := time.Duration(float64(interval) * gapTolerance)
+	previous := startDate.Add(-interval)
+	for _, record := range records {
+		if record.time.Sub(previous) > tolerance {
+			gaps = append(gaps, Gap{Start: previous.Add(interval), End: record.time})
+		}
+		previous = record.time
+	}
+	if endDate.Sub(previous) > tolerance {
+		gaps = append(gaps, Gap{Start: previous.Add(interval), End: endDate})
+	}
+	return gaps
+}
+
+// findWindows returns the runs of consecutive records matching the condition.
+// A record ends a run when it doesn't match, or when it follows a gap.
+func findWindows(records []qualityRecord, interval time.Duration, match func(qualityRecord) bool) []Window {
+	windows := []Window{}
+	tolerance := time.Duration(float64(interval) * gapTolerance)
+	var current *Window
+	var previous time.Time
+	for _, record := range records {
+		if current != nil && (!match(record) || record.time.Sub(previous) > tolerance) {
+			current.Coverage /= float64(current.Records)
+			windows = append(windows, *current)
+			current = nil
+		}
+		if match(record) {
+			if current == nil {
+				current = &Window{Start: record.time}
+			}
+			current.End = record.time.Add(interval)
+			current.Records++
+			current.Coverage += record.coverage
+		}
+		previous = record.time
+	}
+	if current != nil {
+		current.Coverage /= float64(current.Records)
+		windows = append(windows, *current)
+	}
+	return windows
+}
@@ -0,0 +1,107 @@
+package prtgstats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api/prtgtest"
+)
+
+func TestAnalyzeSensors(t *testing.T) {
+	s := prtgtest.NewServer()
+	defer s.Close()
+	device := s.AddDevice(prtgtest.RootID, "Router", "10.0.0.1")
+	sensor := s.AddSensor(device, prtgtest.Sensor{Name: "Ping", Type: "ping", Interval: time.Minute})
+
+	// A record every minute for 2 hours, except between 00:30 and 00:40,
+	// the probe covers half of 01:00 to 01:05, and the sensor is down from 01:30 to 01:35
+	for i := 0; i < 120; i++ {
+		if i >= 30 && i < 40 {
+			continue
+		}
+		coverage, downtime := 100.0, 0.0
+		if i >= 60 && i < 65 {
+			coverage = 50
+		}
+		if i >= 90 && i < 95 {
+			downtime = 100
+		}
+		s.AddHistoricData(sensor, prtgtest.HistoricRecord{
+			Time:     testStart.Add(time.Duration(i) * time.Minute),
+			Coverage: coverage,
+			Values:   map[string]float64{"Ping Time": 12, "Downtime": downtime},
+		})
+	}
+	client := prtg.NewClient(s.URL, prtgtest.DefaultUsername, prtgtest.DefaultPassword)
+
+	endDate := testStart.Add(2 * time.Hour)
+	qualities, err := AnalyzeSensors(client, []int64{sensor}, testStart, endDate, nil)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if len(qualities) != 1 {
+		t.Errorf("There should be 1 quality instead of %v", len(qualities))
+		return
+	}
+	quality := qualities[0]
+	if quality.SensorID != sensor || quality.Interval != time.Minute || quality.Records != 110 || quality.Expected != 120 {
+		t.Errorf("Unexpected quality: %+v", quality)
+	}
+	if math.Abs(quality.Completeness-91.6667) > 0.0001 || math.Abs(quality.Score-89.5833) > 0.0001 {
+		t.Errorf("Completeness and score are %v and %v instead of 91.6667 and 89.5833", quality.Completeness, quality.Score)
+	}
+	if len(quality.Gaps) != 1 || !quality.Gaps[0].Start.Equal(testStart.Add(30*time.Minute)) ||
+		quality.Gaps[0].Duration() != 10*time.Minute {
+		t.Errorf("Unexpected gaps: %+v", quality.Gaps)
+	}
+	expected := Window{Start: testStart.Add(time.Hour), End: testStart.Add(65 * time.Minute), Records: 5, Coverage: 50}
+	if len(quality.LowCoverage) != 1 || quality.LowCoverage[0] != expected {
+		t.Errorf("Low coverage windows are %+v instead of %+v", quality.LowCoverage, expected)
+	}
+	expected = Window{Start: testStart.Add(90 * time.Minute), End: testStart.Add(95 * time.Minute), Records: 5, Coverage: 100}
+	if len(quality.Outages) != 1 || quality.Outages[0] != expected {
+		t.Errorf("Outages are %+v instead of %+v", quality.Outages, expected)
+	}
+
+	// Missing records at the start and end of the period are gaps too
+	wider, err := analyzeTestQuality(client, sensor, testStart.Add(-time.Hour), endDate.Add(time.Hour))
+	if err != nil || len(wider.Gaps) != 3 || !wider.Gaps[0].End.Equal(testStart) || !wider.Gaps[2].Start.Equal(endDate) {
+		t.Errorf("Unexpected gaps: %+v, error: %v", wider, err)
+	}
+
+	// A partial interval at the end expects a record
+	partial, err := AnalyzeQuality(nil, testStart, endDate.Add(30*time.Second), &QualityOptions{Interval: time.Minute})
+	if err != nil || partial.Expected != 121 {
+		t.Errorf("Unexpected quality of a partial interval: %+v, error: %v", partial, err)
+	}
+
+	if _, err := AnalyzeSensors(client, []int64{device}, testStart, endDate, nil); err == nil {
+		t.Errorf("Since the object is not a sensor, an error should occur.")
+	}
+}
+
+func analyzeTestQuality(client *prtg.Client, id int64, startDate, endDate time.Time) (*Quality, error) {
+	histData, err := client.GetHistoricDataRange(id, 0, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	// The interval is estimated from the records
+	return AnalyzeQuality(histData, startDate, endDate, nil)
+}
+
+func TestAnalyzeQualityInvalid(t *testing.T) {
+	if _, err := AnalyzeQuality(nil, testStart, testStart, nil); err == nil {
+		t.Errorf("Since the period is empty, an error should occur.")
+	}
+	if _, err := AnalyzeQuality(nil, testStart, testStart.Add(time.Hour), nil); err == nil {
+		t.Errorf("Since the interval can't be estimated, an error should occur.")
+	}
+	histData := []prtg.PrtgHistoricData{{"datetime": "12/7/2019 12:00:00 AM", "coverage": "-"}}
+	quality, err := AnalyzeQuality(histData, testStart, testStart.Add(time.Hour), &QualityOptions{Interval: time.Hour})
+	if err != nil || quality.Records != 1 || quality.Score != 0 || len(quality.LowCoverage) != 1 {
+		t.Errorf("Unexpected quality of uncovered record: %+v, error: %v", quality, err)
+	}
+}
@@ -0,0 +1,72 @@
+package prtgstats
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Gap is a period without data.
+type Gap struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the gap.
+func (g *Gap) Duration() time.Duration {
+	return g.End.Sub(g.Start)
+}
+
+// Resample averages the points into buckets of the interval, and returns the resampled series
+// with the gaps, the periods between the first and the last bucket which have no point.
+// The buckets are aligned to multiples of the interval since the zero time, e.g. to the hour.
+//
+// Each point is weighted by its coverage, so a partially covered record counts less.
+// The bucket's coverage is the share of the interval covered by its points,
+// e.g. six fully covered 5-minute records make a half covered hour.
+func (s *Series) Resample(interval time.Duration) (Series, []Gap, error) {
+	if err := validateInterval(interval); err != nil {
+		return Series{}, nil, err
+	}
+	if interval < s.Interval {
+		return Series{}, nil, fmt.Errorf("Interval %v should not be finer than the series' interval %v", interval, s.Interval)
+	}
+
+	resampled := Series{Channel: s.Channel, Interval: interval, Points: []Point{}}
+	gaps := []Gap{}
+	var weightedSum, weights, sum, coverage float64
+	var count int
+	var bucket time.Time
+	flush := func() {
+		value := sum / float64(count)
+		if weights > 0 {
+			value = weightedSum / weights
+		}
+		if s.Interval > 0 {
+			coverage = coverage * s.Interval.Seconds() / interval.Seconds()
+		} else {
+			coverage = coverage / float64(count)
+		}
+		resampled.Points = append(resampled.Points, Point{Time: bucket, Value: value, Coverage: math.Min(coverage, 100)})
+	}
+	for _, point := range s.Points {
+		start := point.Time.Truncate(interval)
+		if count > 0 && !start.Equal(bucket) {
+			flush()
+			if next := bucket.Add(interval); next.Before(start) {
+				gaps = append(gaps, Gap{Start: next, End: start})
+			}
+			weightedSum, weights, sum, coverage, count = 0, 0, 0, 0, 0
+		}
+		bucket = start
+		weightedSum += point.Value * point.Coverage
+		weights += point.Coverage
+		sum += point.Value
+		coverage += point.Coverage
+		count++
+	}
+	if count > 0 {
+		flush()
+	}
+	return resampled, gaps, nil
+}
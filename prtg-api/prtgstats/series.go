@@ -0,0 +1,121 @@
+// Package prtgstats aggregates the historic data returned by the prtg client.
+//
+// The historic data is converted into a series per channel, which is summarized
+// into min, max, mean, percentiles, sum, and rate of change,
+// or resampled into a coarser interval weighting each point by its coverage.
+// Traffic sensors are billed on their 95th percentile by Bill,
+// and the gaps and coverage of the records are analyzed by AnalyzeQuality.
+// The anomalous points are flagged by a Detector, e.g. RollingZScore, EWMA, or HourOfWeek.
+//
+//	histData, err := client.GetHistoricData(id, 300, startDate, endDate)
+//	series, err := prtgstats.FromHistoricData(histData, false)
+//	for _, s := range series {
+//		summary := prtgstats.Summarize(s)
+//		hourly, gaps, err := s.Resample(time.Hour)
+//	}
+package prtgstats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+// Point is a channel's value at a record of the historic data.
+type Point struct {
+	// Time is the start of the record's interval in UTC
+	Time  time.Time
+	Value float64
+	// Coverage in percent
+	Coverage float64
+}
+
+// Series contains a channel's points, sorted by time.
+// The records in which the channel has no data are left out.
+type Series struct {
+	Channel string
+	// Interval between the records, e.g. the average of the historic data
+	Interval time.Duration
+	Points   []Point
+}
+
+// FromHistoricData returns a series for each channel of the historic data, sorted by channel's name.
+// The raw values are used if raw is true, otherwise the formatted values.
+func FromHistoricData(histData []prtg.PrtgHistoricData, raw bool) ([]Series, error) {
+	times := make([]time.Time, len(histData))
+	coverages := make([]float64, len(histData))
+	for i, data := range histData {
+		var err error
+		if times[i], err = data.Time(); err != nil {
+			return nil, err
+		}
+		if coverages[i], err = data.Coverage(); err != nil {
+			return nil, err
+		}
+	}
+	interval := estimateInterval(times)
+
+	channels := prtg.HistoricDataChannels(histData)
+	series := make([]Series, 0, len(channels))
+	for _, channel := range channels {
+		s := Series{Channel: channel, Interval: interval, Points: []Point{}}
+		for i, data := range histData {
+			value, ok := data.Value(channel)
+			if raw {
+				value, ok = data.RawValue(channel)
+			}
+			if ok {
+				s.Points = append(s.Points, Point{Time: times[i], Value: value, Coverage: coverages[i]})
+			}
+		}
+		sort.SliceStable(s.Points, func(i, j int) bool { return s.Points[i].Time.Before(s.Points[j].Time) })
+		series = append(series, s)
+	}
+	return series, nil
+}
+
+// Find returns the channel's series, and false if it's not found.
+func Find(series []Series, channel string) (Series, bool) {
+	for _, s := range series {
+		if s.Channel == channel {
+			return s, true
+		}
+	}
+	return Series{}, false
+}
+
+// Values returns the points' values.
+func (s *Series) Values() []float64 {
+	values := make([]float64, len(s.Points))
+	for i, point := range s.Points {
+		values[i] = point.Value
+	}
+	return values
+}
+
+// estimateInterval returns the median of the differences between the sorted times,
+// so a few missing records don't change it.
+func estimateInterval(times []time.Time) time.Duration {
+	sorted := append([]time.Time{}, times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+	deltas := []time.Duration{}
+	for i := 1; i < len(sorted); i++ {
+		if delta := sorted[i].Sub(sorted[i-1]); delta > 0 {
+			deltas = append(deltas, delta)
+		}
+	}
+	if len(deltas) == 0 {
+		return 0
+	}
+	sort.Slice(deltas, func(i, j int) bool { return deltas[i] < deltas[j] })
+	return deltas[len(deltas)/2]
+}
+
+func validateInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("Interval should be more than zero")
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package prtgstats
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api/prtgtest"
+)
+
+var testStart = time.Date(2019, time.December, 7, 0, 0, 0, 0, time.UTC)
+
+// getTestSeries returns the series of a ping sensor recorded every 5 minutes for 2 hours,
+// except the records between 00:30 and 01:00, and the 01:00 record is half covered.
+func getTestSeries(t *testing.T, format prtg.ResponseFormat) []Series {
+	s := prtgtest.NewServer()
+	defer s.Close()
+	device := s.AddDevice(prtgtest.RootID, "Router", "10.0.0.1")
+	sensor := s.AddSensor(device, prtgtest.Sensor{Name: "Ping", Type: "ping"})
+	for i := 0; i < 24; i++ {
+		if i >= 6 && i < 12 {
+			continue
+		}
+		coverage := 100.0
+		if i == 12 {
+			coverage = 50
+		}
+		s.AddHistoricData(sensor, prtgtest.HistoricRecord{
+			Time:     testStart.Add(time.Duration(i) * 5 * time.Minute),
+			Coverage: coverage,
+			Values:   map[string]float64{"Ping Time": float64(i + 1), "Packet Loss": 0},
+		})
+	}
+	client := prtg.NewClient(s.URL, prtgtest.DefaultUsername, prtgtest.DefaultPassword)
+	client.Format = format
+	histData, err := client.GetHistoricData(sensor, 0, testStart, testStart.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("It should be success but error: %v", err)
+	}
+	series, err := FromHistoricData(histData, false)
+	if err != nil {
+		t.Fatalf("It should be success but error: %v", err)
+	}
+	return series
+}
+
+func TestFromHistoricData(t *testing.T) {
+	for _, format := range []prtg.ResponseFormat{prtg.FormatJSON, prtg.FormatXML} {
+		series := getTestSeries(t, format)
+		if len(series) != 2 || series[0].Channel != "Packet Loss" || series[1].Channel != "Ping Time" {
+			t.Errorf("Unexpected series: %+v", series)
+			continue
+		}
+		ping, ok := Find(series, "Ping Time")
+		if !ok || len(ping.Points) != 18 || ping.Interval != 5*time.Minute {
+			t.Errorf("There should be 18 points every 5m instead of %v every %v", len(ping.Points), ping.Interval)
+			continue
+		}
+		if point := ping.Points[6]; !point.Time.Equal(testStart.Add(time.Hour)) || point.Value != 13 || point.Coverage != 50 {
+			t.Errorf("Unexpected point: %+v", point)
+		}
+	}
+	if _, ok := Find(nil, "Ping Time"); ok {
+		t.Errorf("Channel should not be found in empty series")
+	}
+	if _, err := FromHistoricData([]prtg.PrtgHistoricData{{"datetime": "yesterday", "coverage": "100 %"}}, false); err == nil {
+		t.Errorf("Since the datetime is invalid, an error should occur.")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	ping, _ := Find(getTestSeries(t, prtg.FormatJSON), "Ping Time")
+	summary := Summarize(ping)
+	// The values are 1 to 24 except 7 to 12
+	if summary.Count != 18 || summary.Min != 1 || summary.Max != 24 || summary.Sum != 243 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if summary.Mean != 13.5 || summary.P95 != 24 || summary.P99 != 24 {
+		t.Errorf("Mean, p95, and p99 are %v, %v, and %v instead of 13.5, 24, and 24", summary.Mean, summary.P95, summary.P99)
+	}
+	// 23 in 115 minutes
+	if math.Abs(summary.Rate-23.0/6900) > 1e-9 || !summary.End.Equal(testStart.Add(115*time.Minute)) {
+		t.Errorf("Unexpected rate: %+v", summary)
+	}
+
+	if empty := Summarize(Series{Channel: "Empty"}); empty.Count != 0 || empty.Min != 0 || empty.Max != 0 {
+		t.Errorf("Unexpected empty summary: %+v", empty)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{5, 1, 4, 2, 3, 10, 9, 8, 7, 6}
+	expected := map[float64]float64{0: 1, 10: 1, 50: 5, 95: 10, 90: 9, 100: 10, 150: 10}
+	for p, value := range expected {
+		if percentile := Percentile(values, p); percentile != value {
+			t.Errorf("Percentile %v is %v instead of %v", p, percentile, value)
+		}
+	}
+	if values[0] != 5 {
+		t.Errorf("Values should not be sorted in place")
+	}
+	if Percentile(nil, 95) != 0 {
+		t.Errorf("Percentile of empty values should be zero")
+	}
+}
+
+func TestResample(t *testing.T) {
+	ping, _ := Find(getTestSeries(t, prtg.FormatJSON), "Ping Time")
+	halfHourly, gaps, err := ping.Resample(30 * time.Minute)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if halfHourly.Interval != 30*time.Minute || len(halfHourly.Points) != 3 {
+		t.Errorf("There should be 3 points every 30m instead of %+v", halfHourly)
+		return
+	}
+	// 13 is half covered, so (13*50 + (14+...+18)*100) / 550
+	expected := []Point{
+		{Time: testStart, Value: 3.5, Coverage: 100},
+		{Time: testStart.Add(time.Hour), Value: 15.7273, Coverage: 91.6667},
+		{Time: testStart.Add(90 * time.Minute), Value: 21.5, Coverage: 100},
+	}
+	for i, e := range expected {
+		point := halfHourly.Points[i]
+		if !point.Time.Equal(e.Time) || math.Abs(point.Value-e.Value) > 0.0001 || math.Abs(point.Coverage-e.Coverage) > 0.0001 {
+			t.Errorf("Point %v is %+v instead of %+v", i, point, e)
+		}
+	}
+	// The records between 00:30 and 01:00 are missing
+	if len(gaps) != 1 || !gaps[0].Start.Equal(testStart.Add(30*time.Minute)) || gaps[0].Duration() != 30*time.Minute {
+		t.Errorf("Unexpected gaps: %+v", gaps)
+	}
+
+	// Hourly buckets cover the missing records partially
+	hourly, gaps, err := ping.Resample(time.Hour)
+	if err != nil || len(hourly.Points) != 2 || len(gaps) != 0 || hourly.Points[0].Coverage != 50 {
+		t.Errorf("Unexpected hourly series: %+v, gaps: %v, error: %v", hourly, gaps, err)
+	}
+
+	if _, _, err := ping.Resample(time.Minute); err == nil {
+		t.Errorf("Since the interval is finer than the series', an error should occur.")
+	}
+	if _, _, err := ping.Resample(0); err == nil {
+		t.Errorf("Since the interval is zero, an error should occur.")
+	}
+}
@@ -0,0 +1,66 @@
+package prtgstats
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Summary contains the aggregates of a series.
+type Summary struct {
+	Channel string
+	Count   int
+	// Start and End are the time of the first and the last point
+	Start time.Time
+	End   time.Time
+	Min   float64
+	Max   float64
+	Mean  float64
+	Sum   float64
+	P95   float64
+	P99   float64
+	// Rate is the change per second from the first point to the last, zero for a single point
+	Rate float64
+}
+
+// Summarize returns the aggregates of the series.
+// The aggregates of an empty series are zero.
+func Summarize(s Series) Summary {
+	summary := Summary{Channel: s.Channel, Count: len(s.Points)}
+	if len(s.Points) == 0 {
+		return summary
+	}
+	first, last := s.Points[0], s.Points[len(s.Points)-1]
+	summary.Start, summary.End = first.Time, last.Time
+	summary.Min, summary.Max = math.Inf(1), math.Inf(-1)
+	for _, point := range s.Points {
+		summary.Min = math.Min(summary.Min, point.Value)
+		summary.Max = math.Max(summary.Max, point.Value)
+		summary.Sum += point.Value
+	}
+	summary.Mean = summary.Sum / float64(len(s.Points))
+	values := s.Values()
+	summary.P95 = Percentile(values, 95)
+	summary.P99 = Percentile(values, 99)
+	if elapsed := last.Time.Sub(first.Time).Seconds(); elapsed > 0 {
+		summary.Rate = (last.Value - first.Value) / elapsed
+	}
+	return summary
+}
+
+// Percentile returns the p-th percentile of the values by the nearest-rank method,
+// the smallest value which isn't exceeded by p percent of the values.
+// It's zero for empty values, and p is clamped into 0 and 100.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	p = math.Max(0, math.Min(p, 100))
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,186 @@
+// Package prtgsync keeps PRTG's groups, devices, and sensors in line with a declarative state.
+//
+// The desired state describes the objects within a group or probe. Syncer compares it with
+// the sensor tree and the objects' properties, computes a plan of creations, updates, moves,
+// and deletions, and applies the plan through the client's APIs.
+//
+//	state, err := prtgsync.ParseState(file)
+//	syncer := prtgsync.NewSyncer(client)
+//	plan, err := syncer.Sync(state, dryRun)
+//	fmt.Print(plan)
+//
+// The state is read from JSON by ParseState, from YAML by the yamlstate module,
+// or built as Go structs and checked by State.Validate.
+package prtgsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// State is the desired state of the objects within a group or probe.
+type State struct {
+	// Root is the id of the managed group or probe, which isn't changed itself
+	Root    int64    `json:"root" yaml:"root"`
+	Groups  []Group  `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Devices []Device `json:"devices,omitempty" yaml:"devices,omitempty"`
+}
+
+// Group is the desired state of a group.
+// The tags are managed only when they're not nil, and the properties are managed one by one,
+// so the tags and properties which aren't in the state are left as is.
+type Group struct {
+	Name       string            `json:"name" yaml:"name"`
+	Tags       []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Groups     []Group           `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Devices    []Device          `json:"devices,omitempty" yaml:"devices,omitempty"`
+}
+
+// Device is the desired state of a device.
+type Device struct {
+	Name       string            `json:"name" yaml:"name"`
+	Host       string            `json:"host" yaml:"host"`
+	Tags       []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Sensors    []Sensor          `json:"sensors,omitempty" yaml:"sensors,omitempty"`
+}
+
+// Sensor is the desired state of a sensor, identified by its name and type within the device.
+type Sensor struct {
+	Name string `json:"name" yaml:"name"`
+	// Type is the sensor type, e.g. "ping"
+	Type string   `json:"type" yaml:"type"`
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Params are the fields of PRTG's add sensor form, only used when the sensor is created
+	Params     map[string]string `json:"params,omitempty" yaml:"params,omitempty"`
+	Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+// ParseState reads the state from JSON, rejecting unknown fields.
+func ParseState(r io.Reader) (*State, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var state State
+	if err := decoder.Decode(&state); err != nil {
+		return nil, fmt.Errorf("Unable to parse state: %v", err)
+	}
+	if err := state.Validate(); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Validate checks the state's mandatory fields, and that the names are unique within their parent.
+func (s *State) Validate() error {
+	if s.Root < 0 {
+		return fmt.Errorf("Root should be more than or equals to zero")
+	}
+	_, err := s.desired()
+	return err
+}
+
+// Kind is the type of a managed object.
+type Kind string
+
+// Kinds of the managed objects
+const (
+	KindGroup  Kind = "group"
+	KindDevice Kind = "device"
+	KindSensor Kind = "sensor"
+)
+
+// desiredNode is an object of the state, whatever its kind.
+type desiredNode struct {
+	kind       Kind
+	name       string
+	host       string
+	sensorType string
+	// tags are managed only when tagsManaged
+	tags        []string
+	tagsManaged bool
+	params      map[string]string
+	properties  map[string]string
+	children    []*desiredNode
+
+	// live is the object matched in place by the planner
+	live *liveNode
+}
+
+// desired converts the state into a tree of nodes, validating it.
+func (s *State) desired() (*desiredNode, error) {
+	root := &desiredNode{kind: KindGroup}
+	if err := addDesired(root, "", s.Groups, s.Devices); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+func addDesired(parent *desiredNode, path string, groups []Group, devices []Device) error {
+	for _, group := range groups {
+		node := &desiredNode{kind: KindGroup, name: group.Name, tags: group.Tags, tagsManaged: group.Tags != nil,
+			properties: group.Properties}
+		if err := addChild(parent, path, node); err != nil {
+			return err
+		}
+		if err := addDesired(node, joinPath(path, node.name), group.Groups, group.Devices); err != nil {
+			return err
+		}
+	}
+	for _, device := range devices {
+		node := &desiredNode{kind: KindDevice, name: device.Name, host: strings.TrimSpace(device.Host),
+			tags: device.Tags, tagsManaged: device.Tags != nil, properties: device.Properties}
+		if node.host == "" {
+			return fmt.Errorf("Host of device %q should not be empty", joinPath(path, device.Name))
+		}
+		if err := addChild(parent, path, node); err != nil {
+			return err
+		}
+		for _, sensor := range device.Sensors {
+			sensorNode := &desiredNode{kind: KindSensor, name: sensor.Name, sensorType: strings.TrimSpace(sensor.Type),
+				tags: sensor.Tags, tagsManaged: sensor.Tags != nil, params: sensor.Params, properties: sensor.Properties}
+			if sensorNode.sensorType == "" {
+				return fmt.Errorf("Type of sensor %q should not be empty", joinPath(joinPath(path, device.Name), sensor.Name))
+			}
+			if err := addChild(node, joinPath(path, device.Name), sensorNode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func addChild(parent *desiredNode, path string, child *desiredNode) error {
+	if strings.TrimSpace(child.name) == "" {
+		return fmt.Errorf("Name of %v within %q should not be empty", child.kind, displayPath(path))
+	}
+	for _, sibling := range parent.children {
+		if sibling.name == child.name {
+			return fmt.Errorf("Name %q is used twice within %q", child.name, displayPath(path))
+		}
+	}
+	for name := range child.properties {
+		if name == "name" || name == "tags" || name == "host" {
+			return fmt.Errorf("Property %v of %q should be set by its own field", name, joinPath(path, child.name))
+		}
+	}
+	parent.children = append(parent.children, child)
+	return nil
+}
+
+// joinPath returns the path of the child, names are joined by "/".
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "/" + name
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
@@ -0,0 +1,531 @@
+package prtgsync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+// Action is what a change does to an object.
+type Action string
+
+// Actions of the changes
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionMove   Action = "move"
+	ActionDelete Action = "delete"
+)
+
+// PropertyChange is a property changed by an update, or set after a creation.
+type PropertyChange struct {
+	Name string
+	From string
+	To   string
+}
+
+// Change is a step of the plan.
+type Change struct {
+	Action Action
+	Kind   Kind
+	// Path of the object within the root, its names joined by "/"
+	Path string
+	Name string
+	// ID of the existing object, zero when it's created
+	ID int64
+	// ParentPath is where the object is created or moved to
+	ParentPath string
+	// From is the object's path before it's moved
+	From string
+
+	// Host of a created device, and Type of a created sensor
+	Host       string
+	SensorType string
+	// Tags of a created object, nil if they're not managed
+	Tags []string
+	// Params of a created sensor
+	Params map[string]string
+	// Properties changed by an update, or set after a creation
+	Properties []PropertyChange
+}
+
+func (c *Change) String() string {
+	var b strings.Builder
+	switch c.Action {
+	case ActionCreate:
+		fmt.Fprintf(&b, "+ create %v %q", c.Kind, c.Path)
+		switch c.Kind {
+		case KindDevice:
+			fmt.Fprintf(&b, " (host %v)", c.Host)
+		case KindSensor:
+			fmt.Fprintf(&b, " (type %v)", c.SensorType)
+		}
+		if c.Tags != nil {
+			fmt.Fprintf(&b, "\n    tags: %q", strings.Join(c.Tags, " "))
+		}
+	case ActionUpdate:
+		fmt.Fprintf(&b, "~ update %v %q (id %v)", c.Kind, c.Path, c.ID)
+	case ActionMove:
+		fmt.Fprintf(&b, "> move %v %q (id %v) to %q", c.Kind, c.From, c.ID, displayPath(c.ParentPath))
+	case ActionDelete:
+		fmt.Fprintf(&b, "- delete %v %q (id %v)", c.Kind, c.Path, c.ID)
+	}
+	for _, property := range c.Properties {
+		if c.Action == ActionCreate {
+			fmt.Fprintf(&b, "\n    %v: %q", property.Name, property.To)
+			continue
+		}
+		fmt.Fprintf(&b, "\n    %v: %q -> %q", property.Name, property.From, property.To)
+	}
+	return b.String()
+}
+
+// Plan contains the changes which bring PRTG to the desired state, in order.
+type Plan struct {
+	Root    int64
+	Changes []Change
+
+	// ids of the existing objects by path
+	ids map[string]int64
+}
+
+// Empty reports whether PRTG is already in the desired state.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String returns the plan in a human readable form, one change per line.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes, PRTG is in the desired state.\n"
+	}
+	var b strings.Builder
+	counts := map[Action]int{}
+	for i := range p.Changes {
+		b.WriteString(p.Changes[i].String())
+		b.WriteString("\n")
+		counts[p.Changes[i].Action]++
+	}
+	fmt.Fprintf(&b, "Plan: %v to create, %v to update, %v to move, %v to delete.\n",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionMove], counts[ActionDelete])
+	return b.String()
+}
+
+// Syncer plans and applies the desired state through the client.
+type Syncer struct {
+	Client *prtg.Client
+	// Prune deletes the objects within the root which aren't in the state.
+	// Without it, they're left as is. Probes are never deleted.
+	Prune bool
+}
+
+// NewSyncer returns a syncer which doesn't delete anything.
+func NewSyncer(client *prtg.Client) *Syncer {
+	return &Syncer{Client: client}
+}
+
+// Sync plans the state and applies the plan, unless dryRun.
+// The plan is returned even if applying it fails, so the caller can tell what was attempted.
+func (s *Syncer) Sync(state *State, dryRun bool) (*Plan, error) {
+	plan, err := s.Plan(state)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return plan, nil
+	}
+	return plan, s.Apply(plan)
+}
+
+// liveNode is an object of PRTG's sensor tree.
+type liveNode struct {
+	id         int64
+	kind       Kind
+	probe      bool
+	name       string
+	host       string
+	sensorType string
+	tags       []string
+	parent     *liveNode
+	children   []*liveNode
+
+	// used once it's matched by a desired object
+	used bool
+}
+
+func newLiveGroup(group *prtg.SensorTreeGroup, parent *liveNode) *liveNode {
+	node := &liveNode{id: group.GroupId, kind: KindGroup, name: group.GroupName, tags: strings.Fields(group.GroupTags),
+		parent: parent}
+	addLiveChildren(node, group.Groups, group.ProbeNodes, group.Devices)
+	return node
+}
+
+func newLiveProbe(probe *prtg.SensorTreeProbeNode, parent *liveNode) *liveNode {
+	node := &liveNode{id: probe.ProbeId, kind: KindGroup, probe: true, name: probe.ProbeName, parent: parent}
+	addLiveChildren(node, probe.Groups, nil, probe.Devices)
+	return node
+}
+
+func newLiveDevice(device *prtg.SensorTreeDevice, parent *liveNode) *liveNode {
+	node := &liveNode{id: device.DeviceId, kind: KindDevice, name: device.DeviceName, host: device.DeviceHost,
+		tags: strings.Fields(device.DeviceTags), parent: parent}
+	for i := range device.Sensors {
+		sensor := &device.Sensors[i]
+		node.children = append(node.children, &liveNode{id: sensor.SensorId, kind: KindSensor, name: sensor.SensorName,
+			sensorType: sensor.SensorKind, tags: strings.Fields(sensor.SensorTags), parent: node})
+	}
+	return node
+}
+
+func addLiveChildren(node *liveNode, groups []prtg.SensorTreeGroup, probes []prtg.SensorTreeProbeNode, devices []prtg.SensorTreeDevice) {
+	for i := range probes {
+		node.children = append(node.children, newLiveProbe(&probes[i], node))
+	}
+	for i := range groups {
+		node.children = append(node.children, newLiveGroup(&groups[i], node))
+	}
+	for i := range devices {
+		node.children = append(node.children, newLiveDevice(&devices[i], node))
+	}
+}
+
+// live returns the sensor tree of the root as nodes.
+func (s *Syncer) live(root int64) (*liveNode, error) {
+	tree, err := s.Client.GetSensorTree(root)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(tree.Groups) == 1:
+		return newLiveGroup(&tree.Groups[0], nil), nil
+	case len(tree.ProbeNodes) == 1:
+		return newLiveProbe(&tree.ProbeNodes[0], nil), nil
+	}
+	return nil, fmt.Errorf("Root %v should be a group or probe", root)
+}
+
+func (n *liveNode) path() string {
+	if n.parent == nil {
+		return ""
+	}
+	return joinPath(n.parent.path(), n.name)
+}
+
+// matches reports whether the live object may be the desired one.
+// Sensors are matched by their type's id, which PRTG reports in any case, e.g. "SNMPCiscoCBQoS".
+func (n *liveNode) matches(d *desiredNode) bool {
+	return !n.used && n.kind == d.kind && n.name == d.name &&
+		(d.kind != KindSensor || strings.EqualFold(n.sensorType, d.sensorType))
+}
+
+// findChild returns the unused child of the live object matching the desired one.
+func (n *liveNode) findChild(d *desiredNode) *liveNode {
+	if n == nil {
+		return nil
+	}
+	for _, child := range n.children {
+		if child.matches(d) {
+			return child
+		}
+	}
+	return nil
+}
+
+// walk calls fn for every object below n, in depth-first order.
+func (n *liveNode) walk(fn func(*liveNode)) {
+	for _, child := range n.children {
+		fn(child)
+		child.walk(fn)
+	}
+}
+
+// matchInPlace pairs the desired objects with the live ones at the same path.
+func matchInPlace(d *desiredNode, l *liveNode) {
+	for _, child := range d.children {
+		if live := l.findChild(child); live != nil {
+			live.used = true
+			child.live = live
+			matchInPlace(child, live)
+		}
+	}
+}
+
+// planner computes the plan.
+type planner struct {
+	syncer *Syncer
+	plan   *Plan
+	root   *liveNode
+	// properties of the live objects by id
+	properties map[int64]map[string]string
+}
+
+// Plan compares the state with PRTG, and returns the changes without applying them.
+// The objects are matched by their name within the parent, and sensors by their type too.
+// A group or device which isn't found in place but elsewhere within the root is moved.
+//
+// Planning requests the sensor tree once, then one request per declared property of each matched object,
+// since PRTG returns a single property per request. The tags and host are taken from the tree for free,
+// so a state with many properties on many objects is slow to plan.
+func (s *Syncer) Plan(state *State) (*Plan, error) {
+	if err := state.Validate(); err != nil {
+		return nil, err
+	}
+	desired, err := state.desired()
+	if err != nil {
+		return nil, err
+	}
+	root, err := s.live(state.Root)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to plan: %v", err)
+	}
+
+	p := &planner{syncer: s, plan: &Plan{Root: state.Root, ids: map[string]int64{}}, root: root,
+		properties: map[int64]map[string]string{}}
+	matchInPlace(desired, root)
+	if err := p.planChildren(desired, "", root); err != nil {
+		return nil, fmt.Errorf("Unable to plan: %v", err)
+	}
+	if s.Prune {
+		p.planDeletions(root)
+	}
+	return p.plan, nil
+}
+
+// takeElsewhere returns an unused group or device matching the desired one, anywhere within the root.
+func (p *planner) takeElsewhere(d *desiredNode) *liveNode {
+	if d.kind == KindSensor {
+		return nil
+	}
+	var found *liveNode
+	p.root.walk(func(n *liveNode) {
+		if found == nil && !n.probe && n.matches(d) {
+			found = n
+		}
+	})
+	return found
+}
+
+func (p *planner) planChildren(d *desiredNode, path string, l *liveNode) error {
+	for _, child := range d.children {
+		childPath := joinPath(path, child.name)
+		live := child.live
+		if live == nil {
+			// The parent is moved, so its objects are matched now
+			live = l.findChild(child)
+		}
+		if live == nil {
+			if live = p.takeElsewhere(child); live != nil {
+				p.plan.Changes = append(p.plan.Changes, Change{Action: ActionMove, Kind: child.kind, Path: childPath,
+					Name: child.name, ID: live.id, ParentPath: path, From: live.path()})
+			}
+		}
+		if live == nil {
+			p.plan.Changes = append(p.plan.Changes, p.creation(child, path))
+			if err := p.planChildren(child, childPath, nil); err != nil {
+				return err
+			}
+			continue
+		}
+
+		live.used = true
+		p.plan.ids[childPath] = live.id
+		properties, err := p.differences(child, live)
+		if err != nil {
+			return err
+		}
+		if len(properties) > 0 {
+			p.plan.Changes = append(p.plan.Changes, Change{Action: ActionUpdate, Kind: child.kind, Path: childPath,
+				Name: child.name, ID: live.id, Properties: properties})
+		}
+		if err := p.planChildren(child, childPath, live); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *planner) creation(d *desiredNode, parentPath string) Change {
+	change := Change{Action: ActionCreate, Kind: d.kind, Path: joinPath(parentPath, d.name), Name: d.name,
+		ParentPath: parentPath, Host: d.host, SensorType: d.sensorType, Params: d.params}
+	if d.tagsManaged {
+		change.Tags = append([]string{}, d.tags...)
+	}
+	for _, name := range sortedKeys(d.properties) {
+		change.Properties = append(change.Properties, PropertyChange{Name: name, To: d.properties[name]})
+	}
+	return change
+}
+
+// differences returns the properties of the live object which differ from the desired ones.
+func (p *planner) differences(d *desiredNode, l *liveNode) ([]PropertyChange, error) {
+	changes := []PropertyChange{}
+	if d.tagsManaged && !sameTags(d.tags, l.tags) {
+		changes = append(changes, PropertyChange{Name: "tags", From: strings.Join(l.tags, " "), To: strings.Join(d.tags, " ")})
+	}
+	if d.kind == KindDevice && d.host != l.host {
+		changes = append(changes, PropertyChange{Name: "host", From: l.host, To: d.host})
+	}
+	if len(d.properties) == 0 {
+		return changes, nil
+	}
+	properties, err := p.objectProperties(l.id, sortedKeys(d.properties))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range sortedKeys(d.properties) {
+		if value := properties[name]; value != d.properties[name] {
+			changes = append(changes, PropertyChange{Name: name, From: value, To: d.properties[name]})
+		}
+	}
+	return changes, nil
+}
+
+// objectProperties returns the properties of the object, fetching each of them once per plan.
+// PRTG returns a single property per request.
+func (p *planner) objectProperties(id int64, names []string) (map[string]string, error) {
+	properties, ok := p.properties[id]
+	if !ok {
+		properties = map[string]string{}
+		p.properties[id] = properties
+	}
+	for _, name := range names {
+		if _, ok := properties[name]; ok {
+			continue
+		}
+		value, err := p.syncer.Client.GetObjectProperty(id, name)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to get property %v of %v: %v", name, id, err)
+		}
+		properties[name] = value
+	}
+	return properties, nil
+}
+
+// planDeletions deletes the topmost unused objects, except the probes.
+func (p *planner) planDeletions(l *liveNode) {
+	for _, child := range l.children {
+		if !child.used && !child.probe {
+			p.plan.Changes = append(p.plan.Changes, Change{Action: ActionDelete, Kind: child.kind, Path: child.path(),
+				Name: child.name, ID: child.id})
+			continue
+		}
+		p.planDeletions(child)
+	}
+}
+
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Apply applies the plan's changes in order, and stops at the first failure.
+// The plan should be applied soon after it's computed, since it refers to the objects by id.
+func (s *Syncer) Apply(plan *Plan) error {
+	ids := map[string]int64{"": plan.Root}
+	for path, id := range plan.ids {
+		ids[path] = id
+	}
+	for i := range plan.Changes {
+		change := &plan.Changes[i]
+		if err := s.apply(change, ids); err != nil {
+			return fmt.Errorf("Unable to %v %v %q: %v", change.Action, change.Kind, change.Path, err)
+		}
+	}
+	return nil
+}
+
+func (s *Syncer) apply(change *Change, ids map[string]int64) error {
+	client := s.Client
+	switch change.Action {
+	case ActionCreate:
+		parentID, ok := ids[change.ParentPath]
+		if !ok {
+			return fmt.Errorf("Parent %q is not found", displayPath(change.ParentPath))
+		}
+		id, err := s.create(change, parentID)
+		if err != nil {
+			return err
+		}
+		ids[change.Path] = id
+		for _, property := range change.Properties {
+			if err := client.SetObjectProperty(id, property.Name, property.To); err != nil {
+				return err
+			}
+		}
+	case ActionUpdate:
+		for _, property := range change.Properties {
+			if err := client.SetObjectProperty(change.ID, property.Name, property.To); err != nil {
+				return err
+			}
+		}
+	case ActionMove:
+		parentID, ok := ids[change.ParentPath]
+		if !ok {
+			return fmt.Errorf("Parent %q is not found", displayPath(change.ParentPath))
+		}
+		return client.MoveObject(change.ID, parentID)
+	case ActionDelete:
+		return client.DeleteObject(change.ID)
+	default:
+		return fmt.Errorf("Unknown action")
+	}
+	return nil
+}
+
+// create adds the object, and returns its id.
+func (s *Syncer) create(change *Change, parentID int64) (int64, error) {
+	client := s.Client
+	switch change.Kind {
+	case KindGroup:
+		id, err := client.AddGroup(parentID, change.Name)
+		if err != nil {
+			return 0, err
+		}
+		if change.Tags != nil {
+			if err := client.SetObjectProperty(id, "tags", strings.Join(change.Tags, " ")); err != nil {
+				return 0, err
+			}
+		}
+		return id, nil
+	case KindDevice:
+		return client.AddDevice(parentID, change.Name, change.Host, &prtg.DeviceOptions{Tags: change.Tags})
+	case KindSensor:
+		params := map[string]string{}
+		for key, value := range change.Params {
+			params[key] = value
+		}
+		params["name_"] = change.Name
+		if change.Tags != nil {
+			params["tags_"] = strings.Join(change.Tags, " ")
+		}
+		ids, err := client.AddSensor(parentID, change.SensorType, params)
+		if err != nil {
+			return 0, err
+		}
+		// PRTG may create more sensors than asked, the first one is managed
+		return ids[0], nil
+	}
+	return 0, fmt.Errorf("Unknown kind")
+}
@@ -0,0 +1,230 @@
+package prtgsync
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api/prtgtest"
+)
+
+const testState = `{
+    "root": %ROOT%,
+    "groups": [
+        {
+            "name": "Network",
+            "tags": ["network", "core"],
+            "devices": [
+                {
+                    "name": "Router",
+                    "host": "10.0.0.1",
+                    "properties": {"comments": "rack 4"},
+                    "sensors": [
+                        {"name": "Ping", "type": "Ping"},
+                        {"name": "DNS", "type": "dns", "tags": ["dnssensor"], "params": {"interval_": "300|5 minutes"}}
+                    ]
+                },
+                {"name": "Switch", "host": "10.0.0.2"}
+            ]
+        },
+        {
+            "name": "Branch",
+            "tags": ["branch"],
+            "devices": [
+                {"name": "Branch Router", "host": "10.1.0.1", "tags": ["cisco"], "sensors": [{"name": "Ping", "type": "ping"}]}
+            ]
+        }
+    ]
+}`
+
+// newTestServer returns a server with a probe managed by the state, and the state.
+func newTestServer(t *testing.T) (*prtgtest.Server, map[string]int64, *State) {
+	s := prtgtest.NewServer()
+	ids := map[string]int64{}
+	ids["probe"] = s.AddProbe(prtgtest.RootID, "Local Probe")
+	ids["network"] = s.AddGroup(ids["probe"], "Network", "network")
+	ids["router"] = s.AddDevice(ids["network"], "Router", "10.0.0.1", "cisco")
+	s.SetProperty(ids["router"], "comments", "rack 3")
+	ids["ping"] = s.AddSensor(ids["router"], prtgtest.Sensor{Name: "Ping", Type: "ping"})
+	ids["http"] = s.AddSensor(ids["router"], prtgtest.Sensor{Name: "HTTP", Type: "http"})
+	ids["old"] = s.AddGroup(ids["probe"], "Old")
+	ids["switch"] = s.AddDevice(ids["old"], "Switch", "10.0.0.2")
+	ids["legacy"] = s.AddDevice(ids["old"], "Legacy", "10.0.0.9")
+
+	state, err := ParseState(strings.NewReader(strings.Replace(testState, "%ROOT%", "1000", 1)))
+	if err != nil {
+		t.Fatalf("It should be success but error: %v", err)
+	}
+	state.Root = ids["probe"]
+	return s, ids, state
+}
+
+func TestPlan(t *testing.T) {
+	s, ids, state := newTestServer(t)
+	defer s.Close()
+	syncer := NewSyncer(prtg.NewClient(s.URL, prtgtest.DefaultUsername, prtgtest.DefaultPassword))
+
+	// Without prune, nothing is deleted
+	plan, err := syncer.Plan(state)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	expected := []struct {
+		action Action
+		kind   Kind
+		path   string
+	}{
+		{ActionUpdate, KindGroup, "Network"},
+		{ActionUpdate, KindDevice, "Network/Router"},
+		{ActionCreate, KindSensor, "Network/Router/DNS"},
+		{ActionMove, KindDevice, "Network/Switch"},
+		{ActionCreate, KindGroup, "Branch"},
+		{ActionCreate, KindDevice, "Branch/Branch Router"},
+		{ActionCreate, KindSensor, "Branch/Branch Router/Ping"},
+	}
+	if len(plan.Changes) != len(expected) {
+		t.Errorf("There should be %v changes instead of:\n%v", len(expected), plan)
+		return
+	}
+	for i, e := range expected {
+		if c := plan.Changes[i]; c.Action != e.action || c.Kind != e.kind || c.Path != e.path {
+			t.Errorf("Change %v is %v %v %v instead of %v %v %v", i, c.Action, c.Kind, c.Path, e.action, e.kind, e.path)
+		}
+	}
+	if move := plan.Changes[3]; move.ID != ids["switch"] || move.From != "Old/Switch" || move.ParentPath != "Network" {
+		t.Errorf("Unexpected move: %+v", move)
+	}
+	if update := plan.Changes[1]; len(update.Properties) != 1 || update.Properties[0] != (PropertyChange{"comments", "rack 3", "rack 4"}) {
+		t.Errorf("Unexpected update: %+v", update)
+	}
+	output := plan.String()
+	for _, line := range []string{
+		`~ update group "Network" (id `,
+		`    tags: "network" -> "network core"`,
+		`+ create sensor "Network/Router/DNS" (type dns)`,
+		`> move device "Old/Switch" (id `,
+		"Plan: 4 to create, 2 to update, 1 to move, 0 to delete.",
+	} {
+		if !strings.Contains(output, line) {
+			t.Errorf("Plan should contain %q:\n%v", line, output)
+		}
+	}
+
+	// Planning doesn't change anything, and gets the declared properties of the matched objects only
+	properties := 0
+	for _, r := range s.Requests() {
+		switch r.Path {
+		case "/api/table.xml":
+		case "/api/getobjectproperty.htm":
+			properties++
+			if r.Query.Get("id") != fmt.Sprint(ids["router"]) {
+				t.Errorf("Only the router's properties should be requested instead of %v", r.Query.Get("id"))
+			}
+		default:
+			t.Errorf("Planning should not request %v", r.Path)
+		}
+	}
+	if properties != 1 {
+		t.Errorf("Properties should be requested once instead of %v times", properties)
+	}
+
+	// With prune, the unused objects are deleted after the moves
+	syncer.Prune = true
+	plan, err = syncer.Plan(state)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	deletions := []string{}
+	for _, c := range plan.Changes {
+		if c.Action == ActionDelete {
+			deletions = append(deletions, c.Path)
+		}
+	}
+	if strings.Join(deletions, ",") != "Network/Router/HTTP,Old" {
+		t.Errorf("Deleted objects are %v instead of Network/Router/HTTP and Old", deletions)
+	}
+}
+
+func TestSync(t *testing.T) {
+	s, ids, state := newTestServer(t)
+	defer s.Close()
+	client := prtg.NewClient(s.URL, prtgtest.DefaultUsername, prtgtest.DefaultPassword)
+	syncer := NewSyncer(client)
+	syncer.Prune = true
+
+	// Dry run
+	plan, err := syncer.Sync(state, true)
+	if err != nil || plan.Empty() {
+		t.Errorf("There should be changes, error: %v", err)
+		return
+	}
+	if _, err := client.GetSensorDetailTyped(ids["http"]); err != nil {
+		t.Errorf("Dry run should not delete anything: %v", err)
+	}
+
+	if _, err := syncer.Sync(state, false); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	plan, err = syncer.Plan(state)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if !plan.Empty() {
+		t.Errorf("PRTG should be in the desired state, but:\n%v", plan)
+	}
+	if plan.String() != "No changes, PRTG is in the desired state.\n" {
+		t.Errorf("Unexpected empty plan: %v", plan)
+	}
+
+	// The objects are kept, moved, or deleted
+	if comments, _ := client.GetObjectProperty(ids["router"], "comments"); comments != "rack 4" {
+		t.Errorf("Router's comments is %v instead of rack 4", comments)
+	}
+	if _, err := client.GetSensorDetailTyped(ids["ping"]); err != nil {
+		t.Errorf("Ping sensor should be kept: %v", err)
+	}
+	if _, err := client.GetSensorDetailTyped(ids["http"]); err == nil {
+		t.Errorf("HTTP sensor should be deleted")
+	}
+	devices, err := client.GetDeviceList(ids["network"], nil)
+	if err != nil || len(devices) != 2 {
+		t.Errorf("There should be 2 devices in Network instead of %v, error: %v", len(devices), err)
+	}
+	if _, err := client.GetObjectProperty(ids["legacy"], "name"); err == nil {
+		t.Errorf("Legacy device should be deleted with its group")
+	}
+}
+
+func TestParseState(t *testing.T) {
+	state, err := ParseState(strings.NewReader(strings.Replace(testState, "%ROOT%", "1000", 1)))
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if state.Root != 1000 || len(state.Groups) != 2 || state.Groups[0].Devices[0].Sensors[1].Params["interval_"] != "300|5 minutes" {
+		t.Errorf("Unexpected state: %+v", state)
+	}
+	if state.Groups[0].Devices[1].Tags != nil {
+		t.Errorf("Missing tags should not be managed")
+	}
+
+	invalid := map[string]string{
+		"unknown field":     `{"root": 0, "group": []}`,
+		"negative root":     `{"root": -1}`,
+		"empty name":        `{"root": 0, "groups": [{"name": " "}]}`,
+		"empty host":        `{"root": 0, "devices": [{"name": "Router"}]}`,
+		"empty type":        `{"root": 0, "devices": [{"name": "Router", "host": "10.0.0.1", "sensors": [{"name": "Ping"}]}]}`,
+		"duplicated name":   `{"root": 0, "groups": [{"name": "A"}], "devices": [{"name": "A", "host": "10.0.0.1"}]}`,
+		"reserved property": `{"root": 0, "groups": [{"name": "A", "properties": {"tags": "a"}}]}`,
+	}
+	for name, state := range invalid {
+		if _, err := ParseState(strings.NewReader(state)); err == nil {
+			t.Errorf("Since the state has %v, an error should occur.", name)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/haidlir/golang-prtg-api-wrapper/prtg-api/prtgsync/yamlstate
+
+go 1.13
+
+require (
+	github.com/haidlir/golang-prtg-api-wrapper v0.0.0-00010101000000-000000000000
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+// The wrapper has no tagged release including prtgsync yet,
+// so it's built from this repository until one is required with its go.sum lines.
+replace github.com/haidlir/golang-prtg-api-wrapper => ../../..
@@ -0,0 +1,30 @@
+// Package yamlstate reads prtgsync's desired state from YAML.
+//
+//	state, err := yamlstate.ParseState(file)
+//	plan, err := prtgsync.NewSyncer(client).Sync(state, dryRun)
+//
+// It lives in its own module, so prtgsync doesn't depend on a YAML library.
+package yamlstate
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api/prtgsync"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseState reads the state from YAML, rejecting unknown fields.
+// The fields are named like the JSON read by prtgsync.ParseState.
+func ParseState(r io.Reader) (*prtgsync.State, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	var state prtgsync.State
+	if err := decoder.Decode(&state); err != nil {
+		return nil, fmt.Errorf("Unable to parse state: %v", err)
+	}
+	if err := state.Validate(); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
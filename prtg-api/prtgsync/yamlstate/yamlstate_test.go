@@ -0,0 +1,54 @@
+package yamlstate
+
+import (
+	"strings"
+	"testing"
+)
+
+const testState = `
+root: 1000
+groups:
+  - name: Network
+    tags: [network, core]
+    devices:
+      - name: Router
+        host: 10.0.0.1
+        properties:
+          comments: rack 4
+        sensors:
+          - name: Ping
+            type: ping
+          - name: DNS
+            type: dns
+            params:
+              interval_: 300|5 minutes
+      - name: Switch
+        host: 10.0.0.2
+`
+
+func TestParseState(t *testing.T) {
+	state, err := ParseState(strings.NewReader(testState))
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if state.Root != 1000 || len(state.Groups) != 1 || state.Groups[0].Devices[0].Properties["comments"] != "rack 4" ||
+		state.Groups[0].Devices[0].Sensors[1].Params["interval_"] != "300|5 minutes" {
+		t.Errorf("Unexpected state: %+v", state)
+	}
+	if len(state.Groups[0].Tags) != 2 || state.Groups[0].Devices[1].Tags != nil {
+		t.Errorf("Only the group's tags should be managed: %+v", state.Groups[0])
+	}
+
+	invalid := map[string]string{
+		"unknown field": "root: 0\ngroup: []\n",
+		"negative root": "root: -1\n",
+		"empty host":    "root: 0\ndevices:\n  - name: Router\n",
+		"syntax error":  "root: [0\n",
+	}
+	for name, state := range invalid {
+		if _, err := ParseState(strings.NewReader(state)); err == nil {
+			t.Errorf("Since the state has %v, an error should occur.", name)
+		}
+	}
+}
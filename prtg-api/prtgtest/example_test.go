@@ -0,0 +1,30 @@
+package prtgtest_test
+
+import (
+	"fmt"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+	"github.com/haidlir/golang-prtg-api-wrapper/prtg-api/prtgtest"
+)
+
+func ExampleServer() {
+	server := prtgtest.NewServer()
+	defer server.Close()
+	device := server.AddDevice(prtgtest.RootID, "Router", "10.0.0.1")
+	sensor := server.AddSensor(device, prtgtest.Sensor{Name: "Ping", Status: prtgtest.StatusDown, Message: "Timeout"})
+
+	client := prtg.NewClient(server.URL, prtgtest.DefaultUsername, prtgtest.DefaultPassword)
+	detail, err := client.GetSensorDetail(sensor)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(detail.Name, detail.StatusText, detail.LastMessage)
+
+	client.AcknowledgeAlarm(sensor, "on it")
+	status, message := server.Status(sensor)
+	fmt.Println(status, message)
+	// Output:
+	// Ping Down Timeout
+	// Down (Acknowledged) on it
+}
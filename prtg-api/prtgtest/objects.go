@@ -0,0 +1,482 @@
+package prtgtest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ObjectType is the PRTG's type of an object.
+type ObjectType string
+
+// Object types
+const (
+	TypeGroup  ObjectType = "group"
+	TypeProbe  ObjectType = "probenode"
+	TypeDevice ObjectType = "device"
+	TypeSensor ObjectType = "sensor"
+)
+
+// Status is the PRTG's raw status of an object.
+type Status int64
+
+// Statuses, with PRTG's raw value
+const (
+	StatusUnknown          Status = 1
+	StatusScanning         Status = 2
+	StatusUp               Status = 3
+	StatusWarning          Status = 4
+	StatusDown             Status = 5
+	StatusNoProbe          Status = 6
+	StatusPausedByUser     Status = 7
+	StatusPausedByParent   Status = 8
+	StatusUnusual          Status = 10
+	StatusPausedUntil      Status = 12
+	StatusDownAcknowledged Status = 13
+	StatusDownPartial      Status = 14
+)
+
+var statusTexts = map[Status]string{
+	StatusUnknown:          "Unknown",
+	StatusScanning:         "Scanning",
+	StatusUp:               "Up",
+	StatusWarning:          "Warning",
+	StatusDown:             "Down",
+	StatusNoProbe:          "No Probe",
+	StatusPausedByUser:     "Paused",
+	StatusPausedByParent:   "Paused",
+	StatusUnusual:          "Unusual",
+	StatusPausedUntil:      "Paused",
+	StatusDownAcknowledged: "Down (Acknowledged)",
+	StatusDownPartial:      "Down (Partial)",
+}
+
+func (st Status) String() string {
+	if text, ok := statusTexts[st]; ok {
+		return text
+	}
+	return fmt.Sprintf("Status(%d)", int64(st))
+}
+
+// Channel is a sensor's channel.
+type Channel struct {
+	// ID of the channel within the sensor, assigned in order if every channel's id is zero
+	ID    int64
+	Name  string
+	Value float64
+	// Unit appended to the formatted value, e.g. "msec"
+	Unit string
+}
+
+// Sensor describes the sensor added by AddSensor.
+type Sensor struct {
+	Name string
+	// Type is the sensor's type, e.g. "ping"
+	Type string
+	Tags []string
+	// Interval defaults to 60 seconds
+	Interval time.Duration
+	// Status defaults to StatusUp
+	Status  Status
+	Message string
+	// The first channel is the sensor's primary channel
+	Channels []Channel
+	// Cumulated uptime and downtime
+	Uptime   time.Duration
+	Downtime time.Duration
+}
+
+// Trigger is a notification trigger of an object.
+type Trigger struct {
+	// SubID is assigned by the server
+	SubID int64
+	// Type is PRTG's class of the trigger, e.g. "state"
+	Type string
+	// Settings are PRTG's raw settings, e.g. "nodest" or "onnotificationid"
+	Settings map[string]string
+}
+
+// HistoricRecord is a record of the sensor's historic data.
+type HistoricRecord struct {
+	Time time.Time
+	// Coverage in percent
+	Coverage float64
+	// Value per channel's name
+	Values map[string]float64
+}
+
+// Message is an entry of the object's log.
+type Message struct {
+	Time time.Time
+	// Status is logged as its text, e.g. "Down"
+	Status Status
+	Text   string
+}
+
+type object struct {
+	id       int64
+	kind     ObjectType
+	name     string
+	tags     []string
+	host     string
+	sensor   Sensor
+	status   Status
+	message  string
+	parent   *object
+	children []*object
+	history  []HistoricRecord
+	messages []Message
+
+	paused       bool
+	pauseMessage string
+	pausedUntil  time.Time
+	acknowledged bool
+	ackMessage   string
+
+	triggers  []*Trigger
+	nextSubID int64
+	// properties other than name, tags, and host
+	properties map[string]string
+}
+
+// add registers the child under the parent, and panics if the parent can't contain the child.
+func (s *Server) add(parentID int64, child *object, allowed ...ObjectType) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parent, ok := s.objects[parentID]
+	if !ok {
+		panic(fmt.Sprintf("prtgtest: parent object %v not found", parentID))
+	}
+	if !isKind(parent, allowed...) {
+		panic(fmt.Sprintf("prtgtest: %v can't be added to %v %v", child.kind, parent.kind, parentID))
+	}
+	return s.insert(parent, child)
+}
+
+// insert registers the child under the parent, with s.mu held.
+func (s *Server) insert(parent, child *object) int64 {
+	child.id = s.nextID
+	s.nextID++
+	child.parent = parent
+	parent.children = append(parent.children, child)
+	s.objects[child.id] = child
+	return child.id
+}
+
+func isKind(obj *object, kinds ...ObjectType) bool {
+	for _, kind := range kinds {
+		if obj.kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// AddGroup adds a group to a group or probe, and returns its id.
+func (s *Server) AddGroup(parentID int64, name string, tags ...string) int64 {
+	return s.add(parentID, &object{kind: TypeGroup, name: name, tags: tags, status: StatusUp}, TypeGroup, TypeProbe)
+}
+
+// AddProbe adds a probe to a group, and returns its id.
+func (s *Server) AddProbe(parentID int64, name string) int64 {
+	return s.add(parentID, &object{kind: TypeProbe, name: name, status: StatusUp}, TypeGroup)
+}
+
+// AddDevice adds a device to a group or probe, and returns its id.
+func (s *Server) AddDevice(parentID int64, name, host string, tags ...string) int64 {
+	return s.add(parentID, &object{kind: TypeDevice, name: name, host: host, tags: tags, status: StatusUp},
+		TypeGroup, TypeProbe)
+}
+
+// AddSensor adds a sensor to a device, and returns its id.
+func (s *Server) AddSensor(deviceID int64, sensor Sensor) int64 {
+	return s.add(deviceID, newSensorObject(sensor), TypeDevice)
+}
+
+// newSensorObject returns the sensor's object, with the default values applied.
+func newSensorObject(sensor Sensor) *object {
+	if sensor.Status == 0 {
+		sensor.Status = StatusUp
+	}
+	if sensor.Interval == 0 {
+		sensor.Interval = time.Minute
+	}
+	channels := make([]Channel, len(sensor.Channels))
+	copy(channels, sensor.Channels)
+	assignIDs := true
+	for _, channel := range channels {
+		assignIDs = assignIDs && channel.ID == 0
+	}
+	for i := range channels {
+		if assignIDs {
+			channels[i].ID = int64(i)
+		}
+	}
+	sensor.Channels = channels
+	return &object{
+		kind:    TypeSensor,
+		name:    sensor.Name,
+		tags:    sensor.Tags,
+		sensor:  sensor,
+		status:  sensor.Status,
+		message: sensor.Message,
+	}
+}
+
+// sensorObject returns the sensor, and panics if it's not found.
+func (s *Server) sensorObject(id int64) *object {
+	obj, ok := s.objects[id]
+	if !ok || obj.kind != TypeSensor {
+		panic(fmt.Sprintf("prtgtest: sensor %v not found", id))
+	}
+	return obj
+}
+
+// SetSensorStatus changes the sensor's own status, and drops its acknowledgement.
+func (s *Server) SetSensorStatus(id int64, status Status, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := s.sensorObject(id)
+	obj.status, obj.message = status, message
+	obj.acknowledged, obj.ackMessage = false, ""
+}
+
+// SetChannelValue changes the last value of the sensor's channel, adding the channel if it doesn't exist.
+func (s *Server) SetChannelValue(id int64, channel string, value float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := s.sensorObject(id)
+	for i := range obj.sensor.Channels {
+		if obj.sensor.Channels[i].Name == channel {
+			obj.sensor.Channels[i].Value = value
+			return
+		}
+	}
+	var nextID int64
+	for _, ch := range obj.sensor.Channels {
+		if ch.ID >= nextID {
+			nextID = ch.ID + 1
+		}
+	}
+	obj.sensor.Channels = append(obj.sensor.Channels, Channel{ID: nextID, Name: channel, Value: value})
+}
+
+// AddHistoricData appends the records to the sensor's historic data.
+func (s *Server) AddHistoricData(id int64, records ...HistoricRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj := s.sensorObject(id)
+	obj.history = append(obj.history, records...)
+}
+
+// AddMessage appends the messages to the object's log.
+func (s *Server) AddMessage(id int64, messages ...Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[id]
+	if !ok {
+		panic(fmt.Sprintf("prtgtest: object %v not found", id))
+	}
+	obj.messages = append(obj.messages, messages...)
+}
+
+// Status returns the object's current status and message as reported to the client,
+// including the effect of pause and acknowledge actions.
+func (s *Server) Status(id int64) (Status, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[id]
+	if !ok {
+		panic(fmt.Sprintf("prtgtest: object %v not found", id))
+	}
+	return s.effectiveStatus(obj)
+}
+
+// pausedBy returns the nearest paused object from obj up to the root, or nil.
+// The timed pause is lifted once it expires.
+func (s *Server) pausedBy(obj *object) *object {
+	for o := obj; o != nil; o = o.parent {
+		if o.paused && !o.pausedUntil.IsZero() && !s.now().Before(o.pausedUntil) {
+			o.paused, o.pauseMessage, o.pausedUntil = false, "", time.Time{}
+		}
+		if o.paused {
+			return o
+		}
+	}
+	return nil
+}
+
+func (s *Server) effectiveStatus(obj *object) (Status, string) {
+	if pausedBy := s.pausedBy(obj); pausedBy != nil {
+		message := pausedBy.pauseMessage
+		switch {
+		case pausedBy != obj:
+			if message == "" {
+				message = "Paused by parent"
+			}
+			return StatusPausedByParent, message
+		case !obj.pausedUntil.IsZero():
+			if message == "" {
+				message = "Paused until " + obj.pausedUntil.UTC().Format(time.RFC3339)
+			}
+			return StatusPausedUntil, message
+		}
+		if message == "" {
+			message = "Paused by user"
+		}
+		return StatusPausedByUser, message
+	}
+	if obj.acknowledged {
+		return StatusDownAcknowledged, obj.ackMessage
+	}
+	if obj.message == "" && obj.status == StatusUp {
+		return obj.status, "OK"
+	}
+	return obj.status, obj.message
+}
+
+// descendants returns the objects of the kind below obj, in depth-first order.
+func descendants(obj *object, kind ObjectType) []*object {
+	var result []*object
+	for _, child := range obj.children {
+		if child.kind == kind {
+			result = append(result, child)
+		}
+		result = append(result, descendants(child, kind)...)
+	}
+	return result
+}
+
+// ancestor returns the nearest object of the kind above obj, or nil.
+func ancestor(obj *object, kinds ...ObjectType) *object {
+	for o := obj.parent; o != nil; o = o.parent {
+		for _, kind := range kinds {
+			if o.kind == kind {
+				return o
+			}
+		}
+	}
+	return nil
+}
+
+// AddTrigger adds a notification trigger to the object, and returns its subid.
+func (s *Server) AddTrigger(id int64, trigger Trigger) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[id]
+	if !ok {
+		panic(fmt.Sprintf("prtgtest: object %v not found", id))
+	}
+	return addTrigger(obj, trigger.Type, trigger.Settings)
+}
+
+// addTrigger adds the trigger to the object with s.mu held, and returns its subid.
+func addTrigger(obj *object, triggerType string, settings map[string]string) int64 {
+	obj.nextSubID++
+	copied := map[string]string{}
+	for key, value := range settings {
+		copied[key] = value
+	}
+	obj.triggers = append(obj.triggers, &Trigger{SubID: obj.nextSubID, Type: triggerType, Settings: copied})
+	return obj.nextSubID
+}
+
+// Triggers returns the notification triggers defined on the object, without the inherited ones.
+func (s *Server) Triggers(id int64) []Trigger {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[id]
+	if !ok {
+		return nil
+	}
+	triggers := make([]Trigger, 0, len(obj.triggers))
+	for _, trigger := range obj.triggers {
+		copied := *trigger
+		copied.Settings = map[string]string{}
+		for key, value := range trigger.Settings {
+			copied.Settings[key] = value
+		}
+		triggers = append(triggers, copied)
+	}
+	return triggers
+}
+
+type notification struct {
+	id      int64
+	name    string
+	active  bool
+	actions []string
+}
+
+// AddNotification adds a notification template, and returns its id.
+// Its actions are shown as summary, e.g. "Send Email".
+func (s *Server) AddNotification(name string, active bool, actions ...string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.nextID
+	s.nextID++
+	s.notifications = append(s.notifications, &notification{id: id, name: name, active: active, actions: actions})
+	return id
+}
+
+// SetProperty sets the object's property, which is served by the object property API.
+func (s *Server) SetProperty(id int64, name, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[id]
+	if !ok {
+		panic(fmt.Sprintf("prtgtest: object %v not found", id))
+	}
+	obj.setProperty(name, value)
+}
+
+func (obj *object) property(name string) (string, bool) {
+	switch name {
+	case "name":
+		return obj.name, true
+	case "tags":
+		return strings.Join(obj.tags, " "), true
+	case "host":
+		return obj.host, obj.kind == TypeDevice
+	}
+	value, ok := obj.properties[name]
+	return value, ok
+}
+
+func (obj *object) setProperty(name, value string) {
+	switch name {
+	case "name":
+		obj.name = value
+		if obj.kind == TypeSensor {
+			obj.sensor.Name = value
+		}
+	case "tags":
+		obj.tags = strings.Fields(value)
+	case "host":
+		obj.host = value
+	default:
+		if obj.properties == nil {
+			obj.properties = map[string]string{}
+		}
+		obj.properties[name] = value
+	}
+}
+
+// remove unregisters the object and the objects within it, with s.mu held.
+func (s *Server) remove(obj *object) {
+	for _, child := range obj.children {
+		s.remove(child)
+	}
+	delete(s.objects, obj.id)
+}
+
+// detach removes the object from its parent's children, with s.mu held.
+func detach(obj *object) {
+	siblings := obj.parent.children
+	for i, sibling := range siblings {
+		if sibling == obj {
+			obj.parent.children = append(siblings[:i:i], siblings[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,1064 @@
+package prtgtest
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// dateFormat of sDate and eDate queries
+	dateFormat = "2006-01-02-15-04-05"
+	// historicDateFormat of historic data's datetime
+	historicDateFormat = "1/2/2006 3:04:05 PM"
+	historicTimeFormat = "3:04:05 PM"
+)
+
+// prtgEpoch is the origin of PRTG's datetime value, in days.
+var prtgEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+func prtgDateTime(t time.Time) float64 {
+	return t.Sub(prtgEpoch).Hours() / 24
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatValue(value float64, unit string) string {
+	if unit == "" {
+		return formatFloat(value)
+	}
+	return formatFloat(value) + " " + unit
+}
+
+// lookup returns the object of id query, or writes PRTG's error.
+func (s *Server) lookup(w http.ResponseWriter, q url.Values, kinds ...ObjectType) (*object, bool) {
+	id, err := strconv.ParseInt(q.Get("id"), 10, 64)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the id is invalid.")
+		return nil, false
+	}
+	obj, ok := s.objects[id]
+	if !ok || (len(kinds) > 0 && !isKind(obj, kinds...)) {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the selected object cannot be used here.")
+		return nil, false
+	}
+	return obj, true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	body, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func writeXML(w http.ResponseWriter, v interface{}) {
+	body, err := xml.MarshalIndent(v, "", "    ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	w.Write(body)
+}
+
+// sensorDataKeys are the sensor detail's properties, in PRTG's order.
+var sensorDataKeys = []string{"name", "sensortype", "interval", "probename", "parentgroupname", "parentdevicename",
+	"parentdeviceid", "lastvalue", "lastmessage", "favorite", "statustext", "statusid", "lastup", "lastdown",
+	"lastcheck", "uptime", "uptimetime", "downtime", "downtimetime", "updowntotal", "updownsince", "info"}
+
+func (s *Server) sensorData(obj *object) map[string]string {
+	status, message := s.effectiveStatus(obj)
+	data := map[string]string{
+		"name":             obj.name,
+		"sensortype":       string(obj.kind),
+		"probename":        "",
+		"parentgroupname":  "None",
+		"parentdevicename": "None",
+		"parentdeviceid":   "-1000",
+		"lastvalue":        "",
+		"lastmessage":      message,
+		"favorite":         "false",
+		"statustext":       status.String(),
+		"statusid":         fmt.Sprint(int64(status)),
+	}
+	if probe := ancestor(obj, TypeProbe); probe != nil {
+		data["probename"] = probe.name
+	}
+	if group := ancestor(obj, TypeGroup, TypeProbe); group != nil {
+		data["parentgroupname"] = group.name
+	}
+	if obj.kind == TypeSensor {
+		now := fmt.Sprintf("%.10f [0 s ago]", prtgDateTime(s.now()))
+		device := obj.parent
+		data["sensortype"] = obj.sensor.Type
+		data["interval"] = fmt.Sprint(int64(obj.sensor.Interval / time.Second))
+		data["parentdevicename"] = device.name
+		data["parentdeviceid"] = fmt.Sprint(device.id)
+		data["lastvalue"] = "-"
+		if len(obj.sensor.Channels) > 0 {
+			data["lastvalue"] = formatValue(obj.sensor.Channels[0].Value, obj.sensor.Channels[0].Unit)
+		}
+		data["lastcheck"] = now
+		data["lastup"], data["lastdown"] = "-", "-"
+		switch status {
+		case StatusUp, StatusWarning, StatusUnusual:
+			data["lastup"] = now
+		case StatusDown, StatusDownAcknowledged, StatusDownPartial:
+			data["lastdown"] = now
+		}
+		total := obj.sensor.Uptime + obj.sensor.Downtime
+		if total > 0 {
+			data["uptime"] = fmt.Sprintf("%.4f%%", float64(obj.sensor.Uptime)*100/float64(total))
+			data["downtime"] = fmt.Sprintf("%.4f%%", float64(obj.sensor.Downtime)*100/float64(total))
+			data["uptimetime"] = fmt.Sprintf("%v s", int64(obj.sensor.Uptime/time.Second))
+			data["downtimetime"] = fmt.Sprintf("%v s", int64(obj.sensor.Downtime/time.Second))
+		}
+	}
+	return data
+}
+
+func (s *Server) serveSensorDetailsJSON(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"prtgversion": s.version,
+		"sensordata":  s.sensorData(obj),
+	})
+}
+
+func (s *Server) serveSensorDetailsXML(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	type cdataElement struct {
+		XMLName xml.Name
+		Value   string `xml:",cdata"`
+	}
+	data := s.sensorData(obj)
+	elements := []cdataElement{{XMLName: xml.Name{Local: "prtg-version"}, Value: s.version}}
+	for _, key := range sensorDataKeys {
+		elements = append(elements, cdataElement{XMLName: xml.Name{Local: key}, Value: data[key]})
+	}
+	writeXML(w, struct {
+		XMLName  xml.Name `xml:"sensordata"`
+		Elements []cdataElement
+	}{Elements: elements})
+}
+
+// sensorCounts returns the *sens_raw columns of the object's sensors.
+func (s *Server) sensorCounts(obj *object) map[string]int64 {
+	counts := map[string]int64{"downsens_raw": 0, "partialdownsens_raw": 0, "downacksens_raw": 0, "upsens_raw": 0,
+		"warnsens_raw": 0, "pausedsens_raw": 0, "unusualsens_raw": 0, "undefinedsens_raw": 0}
+	for _, sensor := range descendants(obj, TypeSensor) {
+		status, _ := s.effectiveStatus(sensor)
+		switch status {
+		case StatusDown:
+			counts["downsens_raw"]++
+		case StatusDownPartial:
+			counts["partialdownsens_raw"]++
+		case StatusDownAcknowledged:
+			counts["downacksens_raw"]++
+		case StatusUp:
+			counts["upsens_raw"]++
+		case StatusWarning:
+			counts["warnsens_raw"]++
+		case StatusPausedByUser, StatusPausedByParent, StatusPausedUntil:
+			counts["pausedsens_raw"]++
+		case StatusUnusual:
+			counts["unusualsens_raw"]++
+		default:
+			counts["undefinedsens_raw"]++
+		}
+	}
+	return counts
+}
+
+// tableRow returns every supported column of the object, ignoring the requested columns.
+func (s *Server) tableRow(obj *object) map[string]interface{} {
+	status, message := s.effectiveStatus(obj)
+	row := map[string]interface{}{
+		"objid":       obj.id,
+		"name":        obj.name,
+		"tags":        strings.Join(obj.tags, " "),
+		"status":      status.String(),
+		"status_raw":  int64(status),
+		"message_raw": message,
+		"type_raw":    string(obj.kind),
+		"probe":       "",
+		"group":       "",
+		"device":      "",
+	}
+	if probe := ancestor(obj, TypeProbe); probe != nil {
+		row["probe"] = probe.name
+	}
+	if group := ancestor(obj, TypeGroup, TypeProbe); group != nil {
+		row["group"] = group.name
+	}
+	switch obj.kind {
+	case TypeSensor:
+		row["device"] = obj.parent.name
+		row["sensor"] = obj.name
+		row["lastvalue"] = "-"
+		row["lastvalue_raw"] = ""
+		if len(obj.sensor.Channels) > 0 {
+			row["lastvalue"] = formatValue(obj.sensor.Channels[0].Value, obj.sensor.Channels[0].Unit)
+			row["lastvalue_raw"] = obj.sensor.Channels[0].Value
+		}
+		row["type_raw"] = obj.sensor.Type
+	case TypeDevice:
+		row["device"] = obj.name
+		row["host"] = obj.host
+	}
+	if obj.kind != TypeSensor {
+		for k, v := range s.sensorCounts(obj) {
+			row[k] = v
+		}
+	}
+	return row
+}
+
+func (s *Server) serveTableJSON(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	content := q.Get("content")
+	rows := []map[string]interface{}{}
+	switch content {
+	case "groups", "devices", "sensors":
+		kind := map[string]ObjectType{"groups": TypeGroup, "devices": TypeDevice, "sensors": TypeSensor}[content]
+		for _, o := range descendants(obj, kind) {
+			rows = append(rows, s.tableRow(o))
+		}
+	case "triggers":
+		rows = s.triggerRows(obj)
+	case "notifications":
+		for _, n := range s.notifications {
+			rows = append(rows, map[string]interface{}{
+				"objid":      n.id,
+				"name":       n.name,
+				"active":     map[bool]string{true: "Active", false: "Paused"}[n.active],
+				"active_raw": map[bool]int{true: -1, false: 0}[n.active],
+				"tags":       "",
+				"summary":    strings.Join(n.actions, ", "),
+			})
+		}
+	case "messages":
+		var ok bool
+		if rows, ok = s.messageRows(w, obj, q); !ok {
+			return
+		}
+	case "channels":
+		if obj.kind == TypeSensor {
+			for _, channel := range obj.sensor.Channels {
+				rows = append(rows, map[string]interface{}{
+					"objid":         channel.ID,
+					"name":          channel.Name,
+					"lastvalue":     formatValue(channel.Value, channel.Unit),
+					"lastvalue_raw": channel.Value,
+				})
+			}
+		}
+	default:
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("Sorry, the content %q is not supported.", content))
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"prtg-version": s.version,
+		"treesize":     len(rows),
+		content:        rows,
+	})
+}
+
+// messageRows returns the log of the object and the objects within it, newest first,
+// filtered by filter_dstart and filter_dend, and limited by count.
+func (s *Server) messageRows(w http.ResponseWriter, obj *object, q url.Values) ([]map[string]interface{}, bool) {
+	var startDate, endDate time.Time
+	var errStart, errEnd error
+	if v := q.Get("filter_dstart"); v != "" {
+		startDate, errStart = time.Parse(dateFormat, v)
+	}
+	if v := q.Get("filter_dend"); v != "" {
+		endDate, errEnd = time.Parse(dateFormat, v)
+	}
+	count := int64(500)
+	var errCount error
+	if v := q.Get("count"); v != "" {
+		count, errCount = strconv.ParseInt(v, 10, 64)
+	}
+	if errStart != nil || errEnd != nil || errCount != nil {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the filter or count is invalid.")
+		return nil, false
+	}
+
+	type entry struct {
+		obj     *object
+		message Message
+	}
+	var entries []entry
+	objects := append([]*object{obj}, descendants(obj, TypeGroup)...)
+	objects = append(objects, descendants(obj, TypeProbe)...)
+	objects = append(objects, descendants(obj, TypeDevice)...)
+	objects = append(objects, descendants(obj, TypeSensor)...)
+	for _, o := range objects {
+		for _, message := range o.messages {
+			t := message.Time.UTC()
+			if (!startDate.IsZero() && t.Before(startDate)) || (!endDate.IsZero() && t.After(endDate)) {
+				continue
+			}
+			entries = append(entries, entry{o, message})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].message.Time.After(entries[j].message.Time) })
+	if int64(len(entries)) > count {
+		entries = entries[:count]
+	}
+
+	rows := []map[string]interface{}{}
+	for _, e := range entries {
+		row := map[string]interface{}{
+			"objid":        e.obj.id,
+			"datetime":     e.message.Time.UTC().Format(historicDateFormat),
+			"datetime_raw": prtgDateTime(e.message.Time.UTC()),
+			"parent":       "",
+			"type":         string(e.obj.kind),
+			"name":         e.obj.name,
+			"status":       e.message.Status.String(),
+			"status_raw":   int64(e.message.Status),
+			"message":      e.message.Text,
+			"message_raw":  e.message.Text,
+		}
+		if e.obj.parent != nil {
+			row["parent"] = e.obj.parent.name
+		}
+		if e.obj.kind == TypeSensor {
+			row["type"] = e.obj.sensor.Type
+		}
+		rows = append(rows, row)
+	}
+	return rows, true
+}
+
+// treeNode is an element of the sensor tree.
+type treeNode struct {
+	XMLName       xml.Name
+	IDAttr        int64      `xml:"id,attr"`
+	Name          string     `xml:"name"`
+	ID            int64      `xml:"id"`
+	Tags          string     `xml:"tags"`
+	Host          string     `xml:"host,omitempty"`
+	SensorType    string     `xml:"sensortype,omitempty"`
+	SensorKind    string     `xml:"sensorkind,omitempty"`
+	Interval      int64      `xml:"interval,omitempty"`
+	StatusRaw     int64      `xml:"status_raw"`
+	Status        string     `xml:"status,omitempty"`
+	LastValue     string     `xml:"lastvalue,omitempty"`
+	LastValueRaw  string     `xml:"lastvalue_raw,omitempty"`
+	StatusMessage string     `xml:"statusmessage,omitempty"`
+	DownTime      string     `xml:"cumulateddowntime_raw,omitempty"`
+	UpTime        string     `xml:"cumulateduptime_raw,omitempty"`
+	Active        bool       `xml:"active"`
+	Children      []treeNode `xml:",omitempty"`
+}
+
+func (s *Server) treeNode(obj *object) treeNode {
+	status, message := s.effectiveStatus(obj)
+	node := treeNode{
+		XMLName:   xml.Name{Local: string(obj.kind)},
+		IDAttr:    obj.id,
+		Name:      obj.name,
+		ID:        obj.id,
+		Tags:      strings.Join(obj.tags, " "),
+		Host:      obj.host,
+		StatusRaw: int64(status),
+		Active:    s.pausedBy(obj) != obj,
+	}
+	if obj.kind == TypeSensor {
+		// Like PRTG, the tree has the type's display name, and its id as the kind
+		node.SensorType = sensorTypeName(obj.sensor.Type)
+		node.SensorKind = obj.sensor.Type
+		node.Interval = int64(obj.sensor.Interval / time.Second)
+		node.Status = status.String()
+		node.StatusMessage = message
+		node.LastValue = "-"
+		if len(obj.sensor.Channels) > 0 {
+			node.LastValue = formatValue(obj.sensor.Channels[0].Value, obj.sensor.Channels[0].Unit)
+			node.LastValueRaw = formatFloat(obj.sensor.Channels[0].Value)
+		}
+		node.DownTime = formatFloat(obj.sensor.Downtime.Seconds())
+		node.UpTime = formatFloat(obj.sensor.Uptime.Seconds())
+	}
+	for _, child := range obj.children {
+		node.Children = append(node.Children, s.treeNode(child))
+	}
+	return node
+}
+
+func (s *Server) serveTableXML(w http.ResponseWriter, q url.Values) {
+	if content := q.Get("content"); content != "sensortree" {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("Sorry, the content %q is not supported.", content))
+		return
+	}
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	type nodes struct {
+		Node treeNode
+	}
+	writeXML(w, struct {
+		XMLName     xml.Name `xml:"prtg"`
+		PrtgVersion string   `xml:"prtg-version"`
+		Nodes       nodes    `xml:"sensortree>nodes"`
+	}{PrtgVersion: s.version, Nodes: nodes{Node: s.treeNode(obj)}})
+}
+
+// historicItem is a record, or the average of records, within the requested period.
+type historicItem struct {
+	start    time.Time
+	end      time.Time
+	coverage float64
+	values   map[string]float64
+}
+
+func (item *historicItem) datetime() string {
+	if item.end.IsZero() {
+		return item.start.Format(historicDateFormat)
+	}
+	return item.start.Format(historicDateFormat) + " - " + item.end.Format(historicTimeFormat)
+}
+
+// historicData returns the sensor's records within sDate and eDate, averaged per avg seconds.
+func (s *Server) historicData(w http.ResponseWriter, q url.Values) (*object, []historicItem, bool) {
+	obj, ok := s.lookup(w, q, TypeSensor)
+	if !ok {
+		return nil, nil, false
+	}
+	startDate, errStart := time.Parse(dateFormat, q.Get("sDate"))
+	endDate, errEnd := time.Parse(dateFormat, q.Get("eDate"))
+	average, errAvg := strconv.ParseInt(q.Get("avg"), 10, 64)
+	if errStart != nil || errEnd != nil || errAvg != nil || average < 0 {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the period or average is invalid.")
+		return nil, nil, false
+	}
+
+	records := make([]HistoricRecord, 0, len(obj.history))
+	for _, record := range obj.history {
+		t := record.Time.UTC()
+		if !t.Before(startDate) && !t.After(endDate) {
+			records = append(records, record)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+
+	var items []historicItem
+	if average == 0 {
+		for _, record := range records {
+			items = append(items, historicItem{start: record.Time.UTC(), coverage: record.Coverage, values: record.Values})
+		}
+		return obj, items, true
+	}
+	period := time.Duration(average) * time.Second
+	counts := map[string]int{}
+	var n int
+	for _, record := range records {
+		bucketStart := startDate.Add(record.Time.UTC().Sub(startDate) / period * period)
+		if len(items) == 0 || !items[len(items)-1].start.Equal(bucketStart) {
+			items = append(items, historicItem{start: bucketStart, end: bucketStart.Add(period), values: map[string]float64{}})
+			counts, n = map[string]int{}, 0
+		}
+		item := &items[len(items)-1]
+		n++
+		item.coverage += (record.Coverage - item.coverage) / float64(n)
+		for channel, value := range record.Values {
+			counts[channel]++
+			item.values[channel] += (value - item.values[channel]) / float64(counts[channel])
+		}
+	}
+	return obj, items, true
+}
+
+func historicChannels(obj *object, items []historicItem) []string {
+	var channels []string
+	found := map[string]bool{}
+	for _, channel := range obj.sensor.Channels {
+		channels = append(channels, channel.Name)
+		found[channel.Name] = true
+	}
+	var others []string
+	for _, item := range items {
+		for channel := range item.values {
+			if !found[channel] {
+				others = append(others, channel)
+				found[channel] = true
+			}
+		}
+	}
+	sort.Strings(others)
+	return append(channels, others...)
+}
+
+func formatCoverage(coverage float64) string {
+	return fmt.Sprintf("%v %%", math.Round(coverage))
+}
+
+func (s *Server) serveHistoricDataJSON(w http.ResponseWriter, q url.Values) {
+	obj, items, ok := s.historicData(w, q)
+	if !ok {
+		return
+	}
+	channels := historicChannels(obj, items)
+	rows := []map[string]interface{}{}
+	for _, item := range items {
+		row := map[string]interface{}{
+			"datetime":     item.datetime(),
+			"datetime_raw": prtgDateTime(item.start),
+			"coverage":     formatCoverage(item.coverage),
+			"coverage_raw": math.Round(item.coverage * 100),
+		}
+		for _, channel := range channels {
+			if value, ok := item.values[channel]; ok {
+				row[channel] = value
+			} else {
+				row[channel] = ""
+			}
+		}
+		rows = append(rows, row)
+	}
+	writeJSON(w, map[string]interface{}{
+		"prtg-version": s.version,
+		"treesize":     len(rows),
+		"histdata":     rows,
+	})
+}
+
+func (s *Server) serveHistoricDataXML(w http.ResponseWriter, q url.Values) {
+	obj, items, ok := s.historicData(w, q)
+	if !ok {
+		return
+	}
+	type value struct {
+		Channel string `xml:"channel,attr"`
+		Value   string `xml:",chardata"`
+	}
+	type item struct {
+		Datetime    string  `xml:"datetime"`
+		DatetimeRaw string  `xml:"datetime_raw"`
+		Value       []value `xml:"value"`
+		ValueRaw    []value `xml:"value_raw"`
+		Coverage    string  `xml:"coverage"`
+		CoverageRaw string  `xml:"coverage_raw"`
+	}
+	channels := historicChannels(obj, items)
+	units := map[string]string{}
+	for _, channel := range obj.sensor.Channels {
+		units[channel.Name] = channel.Unit
+	}
+	xmlItems := []item{}
+	for _, it := range items {
+		x := item{
+			Datetime:    it.datetime(),
+			DatetimeRaw: fmt.Sprintf("%.10f", prtgDateTime(it.start)),
+			Coverage:    formatCoverage(it.coverage),
+			CoverageRaw: fmt.Sprintf("%010d", int64(math.Round(it.coverage*100))),
+		}
+		for _, channel := range channels {
+			v, ok := it.values[channel]
+			if !ok {
+				continue
+			}
+			x.Value = append(x.Value, value{Channel: channel, Value: formatValue(v, units[channel])})
+			x.ValueRaw = append(x.ValueRaw, value{Channel: channel, Value: fmt.Sprintf("%.4f", v)})
+		}
+		xmlItems = append(xmlItems, x)
+	}
+	writeXML(w, struct {
+		XMLName     xml.Name `xml:"histdata"`
+		PrtgVersion string   `xml:"prtg-version"`
+		Items       []item   `xml:"item"`
+	}{PrtgVersion: s.version, Items: xmlItems})
+}
+
+func (s *Server) serveHistoricDataCSV(w http.ResponseWriter, q url.Values) {
+	obj, items, ok := s.historicData(w, q)
+	if !ok {
+		return
+	}
+	channels := historicChannels(obj, items)
+	units := map[string]string{}
+	for _, channel := range obj.sensor.Channels {
+		units[channel.Name] = channel.Unit
+	}
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	cw.UseCRLF = true
+	header := []string{"Date Time", "Date Time(RAW)"}
+	for _, channel := range channels {
+		header = append(header, channel, channel+"(RAW)")
+	}
+	cw.Write(append(header, "Coverage", "Coverage(RAW)"))
+	for _, it := range items {
+		record := []string{it.datetime(), fmt.Sprintf("%.10f", prtgDateTime(it.start))}
+		for _, channel := range channels {
+			if v, ok := it.values[channel]; ok {
+				record = append(record, formatValue(v, units[channel]), fmt.Sprintf("%.4f", v))
+			} else {
+				record = append(record, "", "")
+			}
+		}
+		record = append(record, formatCoverage(it.coverage), fmt.Sprintf("%010d", int64(math.Round(it.coverage*100))))
+		cw.Write(record)
+	}
+	cw.Flush()
+	w.Header().Set("Content-Type", "text/csv; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// statusCount formats the count like PRTG does, as empty string for zero.
+func statusCount(count int64) string {
+	if count == 0 {
+		return ""
+	}
+	return strconv.FormatInt(count, 10)
+}
+
+func (s *Server) serveStatusJSON(w http.ResponseWriter, q url.Values) {
+	counts := s.sensorCounts(s.root)
+	now := s.now().UTC()
+	writeJSON(w, map[string]interface{}{
+		"PRTGVersion":          s.version,
+		"Version":              s.version,
+		"Clock":                now.Format(historicDateFormat),
+		"jsClock":              now.UnixNano() / int64(time.Millisecond),
+		"Alarms":               statusCount(counts["downsens_raw"]),
+		"PartialAlarms":        statusCount(counts["partialdownsens_raw"]),
+		"AckAlarms":            statusCount(counts["downacksens_raw"]),
+		"UpSens":               statusCount(counts["upsens_raw"]),
+		"WarnSens":             statusCount(counts["warnsens_raw"]),
+		"PausedSens":           statusCount(counts["pausedsens_raw"]),
+		"UnusualSens":          statusCount(counts["unusualsens_raw"]),
+		"UnknownSens":          statusCount(counts["undefinedsens_raw"]),
+		"NewAlarms":            "",
+		"NewMessages":          "0",
+		"NewTickets":           "0",
+		"BackgroundTasks":      "",
+		"EditionType":          "C",
+		"MaxSensorCount":       "",
+		"TrialExpiryDays":      -999999,
+		"CommercialExpiryDays": -999999,
+		"IsCluster":            false,
+		"ClusterNodeName":      "",
+		"ReadOnlyUser":         "false",
+		"IsAdminUser":          true,
+	})
+}
+
+func writeActionOK(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("<html><body>OK</body></html>"))
+}
+
+func (s *Server) servePause(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	switch q.Get("action") {
+	case "0":
+		obj.paused, obj.pauseMessage, obj.pausedUntil = true, q.Get("pausemsg"), time.Time{}
+	case "1":
+		obj.paused, obj.pauseMessage, obj.pausedUntil = false, "", time.Time{}
+	default:
+		s.writeError(w, http.StatusBadRequest, "Sorry, the action is invalid.")
+		return
+	}
+	writeActionOK(w)
+}
+
+func (s *Server) servePauseFor(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	minutes, err := strconv.ParseInt(q.Get("duration"), 10, 64)
+	if err != nil || minutes <= 0 {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the duration is invalid.")
+		return
+	}
+	obj.paused, obj.pauseMessage = true, q.Get("pausemsg")
+	obj.pausedUntil = s.now().Add(time.Duration(minutes) * time.Minute)
+	writeActionOK(w)
+}
+
+func (s *Server) serveAcknowledgeAlarm(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q, TypeSensor)
+	if !ok {
+		return
+	}
+	if status, _ := s.effectiveStatus(obj); status != StatusDown {
+		s.writeError(w, http.StatusBadRequest, "Sorry, only sensors in down state can be acknowledged.")
+		return
+	}
+	obj.acknowledged, obj.ackMessage = true, q.Get("ackmsg")
+	writeActionOK(w)
+}
+
+// writeRedirect responds like PRTG does for the created object.
+func writeRedirect(w http.ResponseWriter, location string) {
+	w.Header().Set("Location", location)
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	w.WriteHeader(http.StatusFound)
+}
+
+func (s *Server) serveAddGroup(w http.ResponseWriter, q url.Values) {
+	parent, ok := s.lookup(w, q, TypeGroup, TypeProbe)
+	if !ok {
+		return
+	}
+	name := strings.TrimSpace(q.Get("name_"))
+	if name == "" {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the name is required.")
+		return
+	}
+	id := s.insert(parent, &object{kind: TypeGroup, name: name, tags: strings.Fields(q.Get("tags_")), status: StatusUp})
+	writeRedirect(w, fmt.Sprintf("/group.htm?id=%v", id))
+}
+
+func (s *Server) serveAddDevice(w http.ResponseWriter, q url.Values) {
+	parent, ok := s.lookup(w, q, TypeGroup, TypeProbe)
+	if !ok {
+		return
+	}
+	name, host := strings.TrimSpace(q.Get("name_")), strings.TrimSpace(q.Get("host_"))
+	if name == "" || host == "" {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the name and the host are required.")
+		return
+	}
+	id := s.insert(parent, &object{kind: TypeDevice, name: name, host: host, tags: strings.Fields(q.Get("tags_")),
+		status: StatusUp})
+	writeRedirect(w, fmt.Sprintf("/device.htm?id=%v", id))
+}
+
+// pendingSensor is a sensor being added, between its start and its creation.
+type pendingSensor struct {
+	deviceID   int64
+	sensorType string
+}
+
+func (s *Server) serveAddSensorStart(w http.ResponseWriter, q url.Values) {
+	device, ok := s.lookup(w, q, TypeDevice)
+	if !ok {
+		return
+	}
+	sensorType := q.Get("sensortype")
+	if sensorType == "" {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the sensor type is required.")
+		return
+	}
+	s.nextTmpID++
+	tmpID := fmt.Sprintf("%v", s.nextTmpID)
+	s.pending[tmpID] = pendingSensor{deviceID: device.id, sensorType: sensorType}
+	writeRedirect(w, fmt.Sprintf("/addsensor4.htm?id=%v&tmpid=%v", device.id, tmpID))
+}
+
+func (s *Server) serveAddSensorProgress(w http.ResponseWriter, q url.Values) {
+	pending, ok := s.pending[q.Get("tmpid")]
+	if !ok {
+		writeJSON(w, map[string]interface{}{"progress": -1, "error": "Sorry, the sensor is not being added."})
+		return
+	}
+	writeJSON(w, map[string]interface{}{
+		"progress":  100,
+		"targeturl": fmt.Sprintf("/addsensor4.htm?id=%v&tmpid=%v", pending.deviceID, q.Get("tmpid")),
+	})
+}
+
+func (s *Server) serveAddSensor(w http.ResponseWriter, q url.Values) {
+	device, ok := s.lookup(w, q, TypeDevice)
+	if !ok {
+		return
+	}
+	tmpID := q.Get("tmpid")
+	pending, ok := s.pending[tmpID]
+	if !ok || pending.deviceID != device.id || pending.sensorType != q.Get("sensortype") {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the sensor is not being added.")
+		return
+	}
+	delete(s.pending, tmpID)
+	sensor := Sensor{Name: strings.TrimSpace(q.Get("name_")), Type: pending.sensorType, Tags: strings.Fields(q.Get("tags_"))}
+	if sensor.Name == "" {
+		sensor.Name = pending.sensorType
+	}
+	// PRTG's interval looks like "300|5 minutes"
+	if interval := strings.SplitN(q.Get("interval_"), "|", 2)[0]; interval != "" {
+		seconds, err := strconv.ParseInt(interval, 10, 64)
+		if err != nil || seconds <= 0 {
+			s.writeError(w, http.StatusBadRequest, "Sorry, the interval is invalid.")
+			return
+		}
+		sensor.Interval = time.Duration(seconds) * time.Second
+	}
+	// PRTG redirects to the sensor when it creates only one
+	id := s.insert(device, newSensorObject(sensor))
+	writeRedirect(w, fmt.Sprintf("/sensor.htm?id=%v", id))
+}
+
+func (s *Server) serveDiscoverNow(w http.ResponseWriter, q url.Values) {
+	if _, ok := s.lookup(w, q, TypeGroup, TypeProbe, TypeDevice); !ok {
+		return
+	}
+	writeActionOK(w)
+}
+
+// sensorTypes is the catalog served for every probe and device.
+var sensorTypes = []map[string]interface{}{
+	{"id": "ping", "name": "Ping", "family": []string{"Availability/Uptime", "Ping"},
+		"description": "Monitors the availability of a device using ICMP echo requests", "manuallyaddable": true},
+	{"id": "http", "name": "HTTP", "family": []string{"Availability/Uptime", "HTTP"},
+		"description": "Monitors a web server using HTTP", "manuallyaddable": true},
+	{"id": "snmptraffic", "name": "SNMP Traffic", "family": []string{"Bandwidth/Traffic", "SNMP"},
+		"description": "Monitors bandwidth and traffic using SNMP", "manuallyaddable": true},
+	{"id": "probehealth", "name": "Probe Health", "family": []string{"System Health"},
+		"description": "Monitors internal system parameters of the probe", "manuallyaddable": false,
+		"notaddablemessage": "This sensor is created by PRTG only."},
+}
+
+// sensorTypeName returns the display name of the sensor type, or the id if it's not in the catalog.
+func sensorTypeName(id string) string {
+	for _, sensorType := range sensorTypes {
+		if sensorType["id"] == id {
+			return sensorType["name"].(string)
+		}
+	}
+	return id
+}
+
+func (s *Server) serveSensorTypes(w http.ResponseWriter, q url.Values) {
+	if _, ok := s.lookup(w, q, TypeProbe, TypeDevice); !ok {
+		return
+	}
+	writeJSON(w, map[string]interface{}{"sensortypes": sensorTypes})
+}
+
+func (s *Server) serveSensorTypesInUse(w http.ResponseWriter, q url.Values) {
+	types := []string{}
+	seen := map[string]bool{}
+	for _, sensor := range descendants(s.root, TypeSensor) {
+		if sensorType := sensor.sensor.Type; sensorType != "" && !seen[sensorType] {
+			seen[sensorType] = true
+			types = append(types, sensorType)
+		}
+	}
+	writeJSON(w, map[string]interface{}{"types": types})
+}
+
+var triggerTypeNames = map[string]string{
+	"state":     "State Trigger",
+	"threshold": "Threshold Trigger",
+	"speed":     "Speed Trigger",
+	"volume":    "Volume Trigger",
+	"change":    "Change Trigger",
+}
+
+// triggerRows returns the triggers of the object and its ancestors, with PRTG's properties as content.
+func (s *Server) triggerRows(obj *object) []map[string]interface{} {
+	rows := []map[string]interface{}{}
+	for owner := obj; owner != nil; owner = owner.parent {
+		for _, trigger := range owner.triggers {
+			content := map[string]string{}
+			for key, value := range trigger.Settings {
+				content[key] = value
+			}
+			content["type"] = trigger.Type
+			content["typename"] = triggerTypeNames[trigger.Type]
+			content["subid"] = fmt.Sprintf("%v", trigger.SubID)
+			content["parentid"] = fmt.Sprintf("%v", owner.id)
+			rows = append(rows, map[string]interface{}{"objid": obj.id, "content": content})
+		}
+	}
+	return rows
+}
+
+// supportedTriggerTypes returns the trigger types which can be added to the object.
+func supportedTriggerTypes(obj *object) []string {
+	if obj.kind == TypeSensor {
+		return []string{"state", "threshold", "speed", "volume", "change"}
+	}
+	return []string{"state"}
+}
+
+func (s *Server) serveTriggerTypes(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	writeJSON(w, map[string]interface{}{"supported": supportedTriggerTypes(obj)})
+}
+
+func (s *Server) serveEditSettings(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	if q.Get("objecttype") != "nodetrigger" {
+		s.writeError(w, http.StatusBadRequest, "Sorry, only the triggers' settings can be edited.")
+		return
+	}
+	subID := q.Get("subid")
+	settings := map[string]string{}
+	for key, values := range q {
+		if name := strings.TrimSuffix(key, "_"+subID); name != key && len(values) > 0 {
+			settings[name] = values[0]
+		}
+	}
+	if subID == "new" {
+		triggerType := q.Get("class")
+		supported := false
+		for _, t := range supportedTriggerTypes(obj) {
+			supported = supported || t == triggerType
+		}
+		if !supported {
+			s.writeError(w, http.StatusBadRequest, "Sorry, the trigger type is not supported by the object.")
+			return
+		}
+		addTrigger(obj, triggerType, settings)
+		writeActionOK(w)
+		return
+	}
+	for _, trigger := range obj.triggers {
+		if fmt.Sprintf("%v", trigger.SubID) == subID {
+			for key, value := range settings {
+				trigger.Settings[key] = value
+			}
+			writeActionOK(w)
+			return
+		}
+	}
+	s.writeError(w, http.StatusBadRequest, "Sorry, the trigger is not found.")
+}
+
+func (s *Server) serveDeleteSub(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	for i, trigger := range obj.triggers {
+		if fmt.Sprintf("%v", trigger.SubID) == q.Get("subid") {
+			obj.triggers = append(obj.triggers[:i], obj.triggers[i+1:]...)
+			writeActionOK(w)
+			return
+		}
+	}
+	s.writeError(w, http.StatusBadRequest, "Sorry, the trigger is not found.")
+}
+
+func (s *Server) serveNotificationTest(w http.ResponseWriter, q url.Values) {
+	for _, n := range s.notifications {
+		if fmt.Sprintf("%v", n.id) == q.Get("id") {
+			writeActionOK(w)
+			return
+		}
+	}
+	s.writeError(w, http.StatusBadRequest, "Sorry, the selected object cannot be used here.")
+}
+
+func (s *Server) serveGetObjectProperty(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	value, ok := obj.property(q.Get("name"))
+	if !ok {
+		value = "(Property not found)"
+	}
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" ?>` + "\n<prtg>\n    <version>" + xmlEscape(s.version) +
+		"</version>\n    <result>" + xmlEscape(value) + "</result>\n</prtg>\n"))
+}
+
+func (s *Server) serveSetObjectProperty(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	name := q.Get("name")
+	if name == "" || (name == "host" && obj.kind != TypeDevice) {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the property is invalid.")
+		return
+	}
+	obj.setProperty(name, q.Get("value"))
+	writeActionOK(w)
+}
+
+func (s *Server) serveMoveObject(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q, TypeGroup, TypeDevice)
+	if !ok {
+		return
+	}
+	if obj == s.root {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the root group cannot be moved.")
+		return
+	}
+	target, ok := s.objects[int64FromQuery(q, "targetid")]
+	if !ok || !isKind(target, TypeGroup, TypeProbe) {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the target is invalid.")
+		return
+	}
+	for ancestor := target; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor == obj {
+			s.writeError(w, http.StatusBadRequest, "Sorry, an object cannot be moved into itself.")
+			return
+		}
+	}
+	detach(obj)
+	obj.parent = target
+	target.children = append(target.children, obj)
+	writeActionOK(w)
+}
+
+func (s *Server) serveDeleteObject(w http.ResponseWriter, q url.Values) {
+	obj, ok := s.lookup(w, q)
+	if !ok {
+		return
+	}
+	if obj == s.root || q.Get("approve") != "1" {
+		s.writeError(w, http.StatusBadRequest, "Sorry, the object cannot be deleted.")
+		return
+	}
+	detach(obj)
+	s.remove(obj)
+	writeActionOK(w)
+}
+
+// int64FromQuery returns the query's number, or -1 if it's invalid.
+func int64FromQuery(q url.Values, key string) int64 {
+	value, err := strconv.ParseInt(q.Get(key), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return value
+}
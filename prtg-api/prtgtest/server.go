@@ -0,0 +1,297 @@
+// Package prtgtest provides an in-process fake PRTG server for testing code built on the prtg client.
+//
+// The server keeps an object tree of groups, probes, devices, and sensors,
+// serves the sensor detail, table, log, sensor tree, status, sensor type, notification, and historic data APIs in JSON, XML, and CSV,
+// checks the credentials, and applies pause, resume, and acknowledge actions to its state.
+// Groups, devices, sensors, and notification triggers can be added through the API too,
+// and objects can be renamed, moved, and deleted, with their properties changed.
+// Faults and latency can be injected per endpoint.
+//
+//	server := prtgtest.NewServer()
+//	defer server.Close()
+//	device := server.AddDevice(prtgtest.RootID, "Router", "10.0.0.1")
+//	sensor := server.AddSensor(device, prtgtest.Sensor{Name: "Ping", Status: prtgtest.StatusDown})
+//	client := prtg.NewClient(server.URL, prtgtest.DefaultUsername, prtgtest.DefaultPassword)
+package prtgtest
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultUsername is the username accepted by a new server.
+	DefaultUsername = "prtgadmin"
+	// DefaultPassword is the password accepted by a new server.
+	DefaultPassword = "prtgadmin"
+	// DefaultPassHash is the password hash accepted by a new server.
+	DefaultPassHash = "1234567890"
+	// DefaultVersion is the PRTG's version reported by a new server.
+	DefaultVersion = "18.2.41.1636"
+	// RootID is the object id of the root group.
+	RootID int64 = 0
+)
+
+// Request is a request received by the server, without the credentials.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+}
+
+// Fault replaces or delays the server's response.
+type Fault struct {
+	// StatusCode of the response. If both StatusCode and Body are empty,
+	// the request is only delayed and then served normally.
+	StatusCode int
+	// Body of the response, e.g. a malformed JSON.
+	Body string
+	// ContentType of the response, defaults to text/html.
+	ContentType string
+	// Delay before responding, cut short if the client gives up.
+	Delay time.Duration
+	// Times is the number of requests affected, zero means until ClearFaults is called.
+	Times int
+}
+
+// Server is a fake PRTG server listening on a local address.
+// Its methods are safe for concurrent use.
+type Server struct {
+	// URL of the server, in the form of http://127.0.0.1:port
+	URL string
+
+	httpServer *httptest.Server
+
+	mu       sync.Mutex
+	username string
+	password string
+	passHash string
+	version  string
+	latency  time.Duration
+	now      func() time.Time
+	faults   map[string][]*Fault
+	requests []Request
+	objects  map[int64]*object
+	root     *object
+	nextID   int64
+	// notifications are the notification templates, which don't belong to the object tree
+	notifications []*notification
+	// pending are the sensors being added, by tmpid
+	pending   map[string]pendingSensor
+	nextTmpID int64
+}
+
+// NewServer starts a server having only the root group,
+// which accepts DefaultUsername with either DefaultPassword or DefaultPassHash.
+// The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		username: DefaultUsername,
+		password: DefaultPassword,
+		passHash: DefaultPassHash,
+		version:  DefaultVersion,
+		now:      time.Now,
+		faults:   map[string][]*Fault{},
+		objects:  map[int64]*object{},
+		nextID:   1000,
+		pending:  map[string]pendingSensor{},
+	}
+	s.root = &object{id: RootID, kind: TypeGroup, name: "Root", status: StatusUp}
+	s.objects[RootID] = s.root
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.httpServer.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// SetCredentials changes the accepted credentials.
+// An empty password or passHash is never accepted.
+func (s *Server) SetCredentials(username, password, passHash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username, s.password, s.passHash = username, password, passHash
+}
+
+// SetVersion changes the reported PRTG's version.
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// SetLatency delays every response.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// SetClock replaces the server's clock, used by timed pauses and sensor's last check.
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// InjectFault applies the fault to the requests of the endpoint's path, e.g. "/api/table.json".
+// An empty path matches every endpoint. Faults of the same path are applied in order.
+func (s *Server) InjectFault(path string, fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[path] = append(s.faults[path], &fault)
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = map[string][]*Fault{}
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := make([]Request, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+// takeFault returns the next fault of the path, consuming it.
+func (s *Server) takeFault(path string) *Fault {
+	for _, p := range []string{path, ""} {
+		faults := s.faults[p]
+		if len(faults) == 0 {
+			continue
+		}
+		fault := *faults[0]
+		if faults[0].Times > 0 {
+			faults[0].Times--
+			if faults[0].Times == 0 {
+				s.faults[p] = faults[1:]
+			}
+		}
+		return &fault
+	}
+	return nil
+}
+
+func (s *Server) authorized(q url.Values) bool {
+	if q.Get("username") != s.username {
+		return false
+	}
+	if password := q.Get("password"); password != "" {
+		return password == s.password
+	}
+	passHash := q.Get("passhash")
+	return passHash != "" && passHash == s.passHash
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	s.mu.Lock()
+	logged := url.Values{}
+	for k, v := range q {
+		if k != "username" && k != "password" && k != "passhash" {
+			logged[k] = v
+		}
+	}
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: logged})
+	latency := s.latency
+	fault := s.takeFault(r.URL.Path)
+	s.mu.Unlock()
+
+	if fault != nil {
+		latency += fault.Delay
+	}
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if fault != nil && (fault.StatusCode != 0 || fault.Body != "") {
+		contentType := fault.ContentType
+		if contentType == "" {
+			contentType = "text/html; charset=UTF-8"
+		}
+		statusCode := fault.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(statusCode)
+		w.Write([]byte(fault.Body))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.authorized(q) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	handler, ok := s.handlers()[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	handler(w, q)
+}
+
+func (s *Server) handlers() map[string]func(http.ResponseWriter, url.Values) {
+	return map[string]func(http.ResponseWriter, url.Values){
+		"/api/getsensordetails.json":    s.serveSensorDetailsJSON,
+		"/api/getsensordetails.xml":     s.serveSensorDetailsXML,
+		"/api/table.json":               s.serveTableJSON,
+		"/api/table.xml":                s.serveTableXML,
+		"/api/historicdata.json":        s.serveHistoricDataJSON,
+		"/api/historicdata.xml":         s.serveHistoricDataXML,
+		"/api/historicdata.csv":         s.serveHistoricDataCSV,
+		"/api/status.json":              s.serveStatusJSON,
+		"/api/pause.htm":                s.servePause,
+		"/api/pauseobjectfor.htm":       s.servePauseFor,
+		"/api/acknowledgealarm.htm":     s.serveAcknowledgeAlarm,
+		"/addgroup2.htm":                s.serveAddGroup,
+		"/adddevice2.htm":               s.serveAddDevice,
+		"/addsensor2.htm":               s.serveAddSensorStart,
+		"/api/getaddsensorprogress.htm": s.serveAddSensorProgress,
+		"/addsensor5.htm":               s.serveAddSensor,
+		"/api/discovernow.htm":          s.serveDiscoverNow,
+		"/api/sensortypes.json":         s.serveSensorTypes,
+		"/api/sensortypesinuse.json":    s.serveSensorTypesInUse,
+		"/api/triggers.json":            s.serveTriggerTypes,
+		"/editsettings":                 s.serveEditSettings,
+		"/deletesub.htm":                s.serveDeleteSub,
+		"/api/notificationtest.htm":     s.serveNotificationTest,
+		"/api/getobjectproperty.htm":    s.serveGetObjectProperty,
+		"/api/setobjectproperty.htm":    s.serveSetObjectProperty,
+		"/moveobjectnow.htm":            s.serveMoveObject,
+		"/api/deleteobject.htm":         s.serveDeleteObject,
+	}
+}
+
+// writeError responds like PRTG does for the invalid request.
+func (s *Server) writeError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	w.WriteHeader(statusCode)
+	w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" ?>` + "\n<prtg>\n    <version>" + xmlEscape(s.version) +
+		"</version>\n    <error>" + xmlEscape(message) + "</error>\n</prtg>\n"))
+}
+
+func xmlEscape(str string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(str))
+	return b.String()
+}
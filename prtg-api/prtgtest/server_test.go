@@ -0,0 +1,407 @@
+package prtgtest
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	prtg "github.com/haidlir/golang-prtg-api-wrapper/prtg-api"
+)
+
+// newTestServer returns a server with a probe, a group, a device, and two sensors.
+func newTestServer() (*Server, map[string]int64) {
+	s := NewServer()
+	ids := map[string]int64{}
+	ids["probe"] = s.AddProbe(RootID, "Local Probe")
+	ids["group"] = s.AddGroup(ids["probe"], "Network", "network")
+	ids["device"] = s.AddDevice(ids["group"], "Router", "10.0.0.1", "cisco")
+	ids["ping"] = s.AddSensor(ids["device"], Sensor{
+		Name:     "Ping",
+		Type:     "ping",
+		Tags:     []string{"pingsensor"},
+		Channels: []Channel{{Name: "Ping Time", Value: 12, Unit: "msec"}, {Name: "Packet Loss", Value: 0, Unit: "%"}},
+		Uptime:   3 * time.Hour,
+		Downtime: time.Hour,
+	})
+	ids["http"] = s.AddSensor(ids["device"], Sensor{Name: "HTTP", Type: "http", Status: StatusDown, Message: "Timeout"})
+	return s, ids
+}
+
+func TestServerReadAPI(t *testing.T) {
+	s, ids := newTestServer()
+	defer s.Close()
+	client := prtg.NewClient(s.URL, DefaultUsername, DefaultPassword)
+
+	for _, format := range []prtg.ResponseFormat{prtg.FormatJSON, prtg.FormatXML} {
+		client.Format = format
+		version, err := client.GetPrtgVersion()
+		if err != nil || version != DefaultVersion {
+			t.Errorf("[%v] Version is %v instead of %v, error: %v", format, version, DefaultVersion, err)
+		}
+		detail, err := client.GetSensorDetailTyped(ids["ping"])
+		if err != nil {
+			t.Errorf("[%v] It should be success but error: %v", format, err)
+			continue
+		}
+		if detail.Name != "Ping" || detail.ParentDeviceID != ids["device"] || detail.ProbeName != "Local Probe" ||
+			detail.ParentGroupName != "Network" || detail.StatusID != int64(StatusUp) || detail.Interval != time.Minute {
+			t.Errorf("[%v] Unexpected sensor detail: %+v", format, detail)
+		}
+		if detail.LastValue != "12 msec" || detail.Uptime != 75 || detail.LastCheck.IsZero() {
+			t.Errorf("[%v] Unexpected sensor detail: %+v", format, detail)
+		}
+	}
+
+	sensors, err := client.GetSensorList(ids["group"], nil)
+	if err != nil || len(sensors) != 2 {
+		t.Errorf("There should be 2 sensors instead of %v, error: %v", len(sensors), err)
+	} else if sensors[1].Sensor != "HTTP" || sensors[1].StatusRaw != int64(StatusDown) || sensors[1].Device != "Router" {
+		t.Errorf("Unexpected sensor: %+v", sensors[1])
+	}
+	devices, err := client.GetDeviceList(RootID, nil)
+	if err != nil || len(devices) != 1 || devices[0].UpSensors != 1 || devices[0].DownSensors != 1 {
+		t.Errorf("Unexpected devices: %+v, error: %v", devices, err)
+	}
+	groups, err := client.GetGroupList(RootID, nil)
+	if err != nil || len(groups) != 1 || groups[0].Name != "Network" || groups[0].Probe != "Local Probe" {
+		t.Errorf("Unexpected groups: %+v, error: %v", groups, err)
+	}
+	channels, err := client.GetChannelList(ids["ping"], nil)
+	if err != nil || len(channels) != 2 || channels[1].ObjectId != 1 {
+		t.Errorf("Unexpected channels: %+v, error: %v", channels, err)
+	}
+
+	tree, err := client.GetSensorTree(RootID)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	} else if len(tree.Groups) != 1 || len(tree.Groups[0].ProbeNodes) != 1 {
+		t.Errorf("Root should contain a probe: %+v", tree)
+	} else {
+		probe := tree.Groups[0].ProbeNodes[0]
+		if probe.ProbeId != ids["probe"] || len(probe.Groups) != 1 || len(probe.Groups[0].Devices) != 1 {
+			t.Errorf("Unexpected probe: %+v", probe)
+		} else if sensors := probe.Groups[0].Devices[0].Sensors; len(sensors) != 2 || sensors[0].SensorLastValue != 12 ||
+			sensors[1].SensorStatusRaw != int64(StatusDown) || sensors[0].SensorCumulatedUpTime != 10800 {
+			t.Errorf("Unexpected sensors: %+v", sensors)
+		}
+	}
+
+	status, err := client.GetStatus()
+	if err != nil || status.Version != DefaultVersion || status.Alarms != 1 || status.UpSensors != 1 || status.Clock.IsZero() {
+		t.Errorf("Unexpected status: %+v, error: %v", status, err)
+	}
+
+	// Unknown object
+	if _, err := client.GetSensorDetail(9999); err == nil {
+		t.Errorf("Since the object doesn't exist, an error should occur.")
+	}
+}
+
+func TestServerHistoricData(t *testing.T) {
+	s, ids := newTestServer()
+	defer s.Close()
+	client := prtg.NewClient(s.URL, DefaultUsername, DefaultPassword)
+
+	start := time.Date(2019, time.December, 7, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10; i++ {
+		s.AddHistoricData(ids["ping"], HistoricRecord{
+			Time:     start.Add(time.Duration(i) * time.Minute),
+			Coverage: 100,
+			Values:   map[string]float64{"Ping Time": float64(10 + i), "Packet Loss": 0},
+		})
+	}
+	end := start.Add(time.Hour)
+
+	histData, err := client.GetHistoricData(ids["ping"], 0, start, end)
+	if err != nil || len(histData) != 10 {
+		t.Errorf("There should be 10 records instead of %v, error: %v", len(histData), err)
+	} else if histData[0]["datetime"] != "12/7/2019 12:00:00 AM" || histData[9]["Ping Time"] != float64(19) {
+		t.Errorf("Unexpected records: %v", histData)
+	}
+
+	// Average per 5 minutes
+	histData, err = client.GetHistoricDataXML(ids["ping"], 300, start, end)
+	if err != nil || len(histData) != 2 {
+		t.Errorf("There should be 2 records instead of %v, error: %v", len(histData), err)
+	} else if histData[0]["datetime"] != "12/7/2019 12:00:00 AM - 12:05:00 AM" || histData[1]["Ping Time"] != "17.0000" {
+		t.Errorf("Unexpected records: %v", histData)
+	}
+
+	var buf bytes.Buffer
+	if err := client.GetHistoricDataCSV(&buf, ids["ping"], 0, start, end); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\r\n"); len(lines) != 11 ||
+		lines[0] != "Date Time,Date Time(RAW),Ping Time,Ping Time(RAW),Packet Loss,Packet Loss(RAW),Coverage,Coverage(RAW)" {
+		t.Errorf("Unexpected CSV:\n%v", buf.String())
+	}
+
+	// Historic data is only available for sensor
+	if _, err := client.GetHistoricData(ids["device"], 0, start, end); err == nil {
+		t.Errorf("Since the object is not a sensor, an error should occur.")
+	}
+}
+
+func TestServerMessages(t *testing.T) {
+	s, ids := newTestServer()
+	defer s.Close()
+	client := prtg.NewClient(s.URL, DefaultUsername, DefaultPassword)
+
+	start := time.Date(2019, time.December, 7, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	s.AddMessage(ids["ping"],
+		Message{Time: start.Add(10 * time.Minute), Status: StatusDown, Text: "Request timed out"},
+		Message{Time: start.Add(20 * time.Minute), Status: StatusUp, Text: "OK"},
+		Message{Time: end.Add(time.Minute), Status: StatusDown, Text: "Request timed out"},
+	)
+	s.AddMessage(ids["http"], Message{Time: start.Add(30 * time.Minute), Status: StatusDown, Text: "Timeout"})
+	for i := 0; i < 6; i++ {
+		downtime := 0.0
+		if i == 1 {
+			downtime = 100
+		}
+		s.AddHistoricData(ids["ping"], HistoricRecord{
+			Time:     start.Add(time.Duration(i) * 10 * time.Minute),
+			Coverage: 100,
+			Values:   map[string]float64{"Ping Time": 12, "Downtime": downtime},
+		})
+	}
+
+	// The log of the device includes its sensors'
+	messages, err := client.GetMessages(ids["device"], start, end)
+	if err != nil || len(messages) != 3 {
+		t.Errorf("There should be 3 messages instead of %v, error: %v", len(messages), err)
+	} else if messages[0].ObjectId != ids["ping"] || messages[0].Status != "Down" || messages[0].Type != "ping" ||
+		messages[0].Parent != "Router" || !messages[0].DateTime.Equal(start.Add(10*time.Minute)) {
+		t.Errorf("Unexpected message: %+v", messages[0])
+	}
+
+	sla, err := client.GetSLA(ids["ping"], 600, start, end)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if sla.Downtime != 10*time.Minute || len(sla.Outages) != 1 || sla.MTTR != 10*time.Minute {
+		t.Errorf("Unexpected SLA: %+v", sla)
+	}
+}
+
+func TestServerActions(t *testing.T) {
+	s, ids := newTestServer()
+	defer s.Close()
+	now := time.Date(2019, time.December, 7, 0, 0, 0, 0, time.UTC)
+	s.SetClock(func() time.Time { return now })
+	client := prtg.NewClientWithHashedPass(s.URL, DefaultUsername, DefaultPassHash)
+
+	// Pausing the device pauses its sensors
+	if err := client.PauseObject(ids["device"], "maintenance"); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if status, message := s.Status(ids["ping"]); status != StatusPausedByParent || message != "maintenance" {
+		t.Errorf("Sensor's status is %v (%v) instead of paused by parent", status, message)
+	}
+	if err := client.ResumeObject(ids["device"]); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if status, _ := s.Status(ids["ping"]); status != StatusUp {
+		t.Errorf("Sensor's status is %v instead of up", status)
+	}
+
+	// Timed pause expires
+	if err := client.PauseObjectFor(ids["ping"], "", 30*time.Minute); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if status, _ := s.Status(ids["ping"]); status != StatusPausedUntil {
+		t.Errorf("Sensor's status is %v instead of paused until", status)
+	}
+	now = now.Add(time.Hour)
+	if status, _ := s.Status(ids["ping"]); status != StatusUp {
+		t.Errorf("Sensor's status is %v instead of up after the pause expires", status)
+	}
+
+	// Only down sensor could be acknowledged
+	if err := client.AcknowledgeAlarm(ids["ping"], ""); err == nil {
+		t.Errorf("Since the sensor is up, an error should occur.")
+	}
+	if err := client.AcknowledgeAlarm(ids["http"], "on it"); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if status, message := s.Status(ids["http"]); status != StatusDownAcknowledged || message != "on it" {
+		t.Errorf("Sensor's status is %v (%v) instead of acknowledged", status, message)
+	}
+	s.SetSensorStatus(ids["http"], StatusDown, "Timeout again")
+	if status, _ := s.Status(ids["http"]); status != StatusDown {
+		t.Errorf("Sensor's status is %v instead of down", status)
+	}
+
+	// Credentials are not logged
+	for _, r := range s.Requests() {
+		if r.Query.Get("passhash") != "" || r.Query.Get("username") != "" {
+			t.Errorf("Request %v should not contain the credentials", r.Path)
+		}
+	}
+}
+
+func TestServerCreate(t *testing.T) {
+	s, ids := newTestServer()
+	defer s.Close()
+	client := prtg.NewClient(s.URL, DefaultUsername, DefaultPassword)
+
+	group, err := client.AddGroup(ids["probe"], "Branch")
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	device, err := client.AddDevice(group, "Switch", "10.0.1.1", &prtg.DeviceOptions{Tags: []string{"hp"}})
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	types, err := client.GetSensorTypes(device)
+	if err != nil || len(types) == 0 || types[0].ID != "ping" || !types[0].ManuallyAddable || !types[0].InUse {
+		t.Errorf("Unexpected sensor types: %+v, error: %v", types, err)
+	}
+	sensors, err := client.AddSensor(device, "ping", map[string]string{"name_": "Ping", "interval_": "300|5 minutes"})
+	if err != nil || len(sensors) != 1 {
+		t.Errorf("There should be 1 new sensor instead of %v, error: %v", len(sensors), err)
+		return
+	}
+	detail, err := client.GetSensorDetailTyped(sensors[0])
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if detail.Name != "Ping" || detail.ParentDeviceID != device || detail.ParentGroupName != "Branch" ||
+		detail.Interval != 5*time.Minute {
+		t.Errorf("Unexpected sensor detail: %+v", detail)
+	}
+	if err := client.RunAutoDiscovery(group, nil); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+
+	// Sensors are added to devices only
+	if _, err := client.AddSensor(group, "ping", nil); err == nil {
+		t.Errorf("Since the parent is a group, an error should occur.")
+	}
+	if _, err := client.AddDevice(ids["device"], "Switch", "10.0.1.2", nil); err == nil {
+		t.Errorf("Since the parent is a device, an error should occur.")
+	}
+}
+
+func TestServerTriggers(t *testing.T) {
+	s, ids := newTestServer()
+	defer s.Close()
+	client := prtg.NewClient(s.URL, DefaultUsername, DefaultPassword)
+	s.AddTrigger(ids["group"], Trigger{Type: "state", Settings: map[string]string{"nodest": "0", "onnotificationid": "300|Email"}})
+
+	subID, err := client.AddTrigger(ids["ping"], &prtg.PrtgTrigger{
+		Type: prtg.TriggerThreshold, Channel: "0", Condition: prtg.ConditionAbove, Threshold: 100,
+		OnNotification: prtg.NotificationAction{ID: 302, Name: "Ticket"},
+	})
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	triggers, err := client.GetTriggers(ids["ping"])
+	if err != nil || len(triggers) != 2 {
+		t.Errorf("There should be 2 triggers instead of %v, error: %v", len(triggers), err)
+		return
+	}
+	if own := triggers[0]; own.SubID != subID || own.Inherited || own.Threshold != 100 || own.OnNotification.ID != 302 {
+		t.Errorf("Unexpected trigger: %+v", own)
+	}
+	if inherited := triggers[1]; !inherited.Inherited || inherited.ParentID != ids["group"] || inherited.State != prtg.StateDown {
+		t.Errorf("Unexpected inherited trigger: %+v", inherited)
+	}
+
+	// Only state trigger is supported by a device
+	if _, err := client.AddTrigger(ids["device"], &prtg.PrtgTrigger{Type: prtg.TriggerChange}); err == nil {
+		t.Errorf("Since the device doesn't support change trigger, an error should occur.")
+	}
+
+	edited := triggers[0]
+	edited.Threshold = 250
+	if err := client.EditTrigger(ids["ping"], subID, &edited); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if saved := s.Triggers(ids["ping"]); len(saved) != 1 || saved[0].Settings["threshold"] != "250" {
+		t.Errorf("Unexpected saved triggers: %+v", saved)
+	}
+	if err := client.RemoveTrigger(ids["ping"], subID); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if saved := s.Triggers(ids["ping"]); len(saved) != 0 {
+		t.Errorf("Trigger should be removed: %+v", saved)
+	}
+	if err := client.RemoveTrigger(ids["ping"], subID); err == nil {
+		t.Errorf("Since the trigger has been removed, an error should occur.")
+	}
+}
+
+func TestServerNotifications(t *testing.T) {
+	s, _ := newTestServer()
+	defer s.Close()
+	client := prtg.NewClient(s.URL, DefaultUsername, DefaultPassword)
+	email := s.AddNotification("Email to Admin", true, "Send Email", "Add Entry to Event Log")
+	s.AddNotification("Ticket to NOC", false)
+
+	notifications, err := client.GetNotifications()
+	if err != nil || len(notifications) != 2 {
+		t.Errorf("There should be 2 notifications instead of %v, error: %v", len(notifications), err)
+		return
+	}
+	if n := notifications[0]; n.ObjectId != email || !n.Active || len(n.Actions) != 2 {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+	if n := notifications[1]; n.Active || len(n.Actions) != 0 {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+	if err := client.TestNotification(email); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if err := client.TestNotification(9999); err == nil {
+		t.Errorf("Since the notification doesn't exist, an error should occur.")
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	s, ids := newTestServer()
+	defer s.Close()
+
+	// Wrong credentials
+	client := prtg.NewClient(s.URL, DefaultUsername, "wrong")
+	_, err := client.GetSensorDetail(ids["ping"])
+	var statusErr *prtg.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("It should be unauthorized but error: %v", err)
+	}
+
+	client = prtg.NewClient(s.URL, DefaultUsername, DefaultPassword)
+	client.Format = prtg.FormatJSON
+
+	// Fault is applied once
+	s.InjectFault("/api/getsensordetails.json", Fault{StatusCode: http.StatusServiceUnavailable, Times: 1})
+	if _, err := client.GetSensorDetail(ids["ping"]); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("It should be unavailable but error: %v", err)
+	}
+	if _, err := client.GetSensorDetail(ids["ping"]); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+
+	// Malformed body until cleared
+	s.InjectFault("", Fault{Body: "{", ContentType: "application/json"})
+	if _, err := client.GetSensorDetail(ids["ping"]); err == nil {
+		t.Errorf("Since the body is malformed, an error should occur.")
+	}
+	s.ClearFaults()
+
+	// Latency beyond the client's timeout
+	s.SetLatency(200 * time.Millisecond)
+	client.SetContextTimeout(50)
+	if _, err := client.GetSensorDetail(ids["ping"]); err == nil {
+		t.Errorf("Since the timeout is reached, an error should occur.")
+	}
+}
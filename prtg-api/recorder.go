@@ -0,0 +1,236 @@
+package prtg
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// scrubCredentials returns the query without the credentials.
+func scrubCredentials(q url.Values) url.Values {
+	scrubbed := url.Values{}
+	for k, v := range q {
+		scrubbed[k] = v
+	}
+	for _, param := range credentialQueryKeys {
+		scrubbed.Del(param)
+	}
+	return scrubbed
+}
+
+// fixtureContents are the names of the fixtures' content by endpoint, when it's not the endpoint's name.
+var fixtureContents = map[string]string{
+	"getsensordetails": "sensor",
+	"historicdata":     "histdata",
+	"downloadreport":   "report",
+}
+
+// FixtureName returns the file name of the request's fixture, named by its content and object
+// like the fixtures directory, e.g. prtg_sensor_9321.json, prtg_sensor-list_9301.json, or prtg_histdata_14254.json.
+// The credentials and the query other than the content and id are ignored,
+// so requests of the same content and object share the name. See Recorder for how they're told apart.
+func FixtureName(u *url.URL) string {
+	q := u.Query()
+	ext := path.Ext(u.Path)
+	content := strings.TrimSuffix(path.Base(u.Path), ext)
+	if name, ok := fixtureContents[content]; ok {
+		content = name
+	}
+	switch {
+	case content == "table" && strings.HasSuffix(q.Get("content"), "s"):
+		// e.g. sensors is a sensor-list
+		content = strings.TrimSuffix(q.Get("content"), "s") + "-list"
+	case content == "table" && q.Get("content") != "":
+		content = q.Get("content")
+	case content == "report" && q.Get("format") != "":
+		ext = "." + q.Get("format")
+	}
+	name := "prtg_" + content
+	if id := q.Get("id"); id != "" {
+		name += "_" + id
+	}
+	return name + ext
+}
+
+// hashedFixtureName returns the fixture's name with a hash of the whole query without the credentials,
+// for a request whose FixtureName is taken by another query.
+func hashedFixtureName(u *url.URL) string {
+	name := FixtureName(u)
+	ext := path.Ext(name)
+	// Encode sorts the keys
+	h := fnv.New32a()
+	h.Write([]byte(scrubCredentials(u.Query()).Encode()))
+	return fmt.Sprintf("%v_%08x%v", strings.TrimSuffix(name, ext), h.Sum32(), ext)
+}
+
+// exchangeRequest returns the request line kept in the fixture's exchange file, without the credentials.
+func exchangeRequest(req *http.Request) string {
+	u := url.URL{Path: req.URL.Path, RawQuery: scrubCredentials(req.URL.Query()).Encode()}
+	return fmt.Sprintf("%v %v", req.Method, u.RequestURI())
+}
+
+// recordedRequest returns the request line of the fixture's exchange file, false if there is no exchange file.
+func recordedRequest(p string) (string, bool) {
+	exchange, err := ioutil.ReadFile(p + exchangeFileExt)
+	if err != nil {
+		return "", false
+	}
+	return strings.SplitN(string(exchange), "\n", 2)[0], true
+}
+
+// exchangeFileExt is appended to the fixture's name to keep the request without the credentials,
+// and the response's status. A fixture without it is served with status 200.
+const exchangeFileExt = ".http"
+
+// Recorder is an http.RoundTripper which saves PRTG's responses into a fixtures directory
+// named by FixtureName, to be served back later by Replayer.
+// When the name is already taken by another query, e.g. other columns of the same list,
+// a hash of the query is appended to the name.
+// Next to each response body, a file with .http extension keeps the request and the response's status, e.g.
+//
+//	GET /api/table.json?columns=objid%2Csensor&content=sensors&id=9301
+//	200 OK
+//
+//	client := prtg.NewClient(server, username, password)
+//	client.Transport = prtg.NewRecorder("fixtures/18.2", nil)
+type Recorder struct {
+	// Dir is the fixtures directory, created if it doesn't exist.
+	Dir string
+
+	// Transport sends the requests, the client's default transport is used when nil.
+	Transport http.RoundTripper
+
+	mu sync.Mutex
+}
+
+// NewRecorder returns a recorder saving the responses received through transport into dir.
+func NewRecorder(dir string, transport http.RoundTripper) *Recorder {
+	return &Recorder{Dir: dir, Transport: transport}
+}
+
+// RoundTrip sends the request and saves its response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = defaultTransport()
+	}
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read response body: %v", err)
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if err := r.save(req, res.StatusCode, body); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *Recorder) save(req *http.Request, statusCode int, body []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.MkdirAll(r.Dir, 0755); err != nil {
+		return fmt.Errorf("Unable to create fixtures directory: %v", err)
+	}
+	request := exchangeRequest(req)
+	p := filepath.Join(r.Dir, FixtureName(req.URL))
+	if recorded, ok := recordedRequest(p); ok && recorded != request {
+		p = filepath.Join(r.Dir, hashedFixtureName(req.URL))
+	}
+	if err := ioutil.WriteFile(p, body, 0644); err != nil {
+		return fmt.Errorf("Unable to write fixture: %v", err)
+	}
+	exchange := fmt.Sprintf("%v\n%d %v\n", request, statusCode, http.StatusText(statusCode))
+	if err := ioutil.WriteFile(p+exchangeFileExt, []byte(exchange), 0644); err != nil {
+		return fmt.Errorf("Unable to write fixture: %v", err)
+	}
+	return nil
+}
+
+// Replayer is an http.RoundTripper which serves the responses saved by Recorder,
+// without reaching any server.
+//
+//	client := prtg.NewClient("http://prtg.example", "user", "pass")
+//	client.Transport = prtg.NewReplayer("fixtures/18.2")
+type Replayer struct {
+	// Dir is the fixtures directory.
+	Dir string
+}
+
+// NewReplayer returns a replayer serving the fixtures of dir.
+func NewReplayer(dir string) *Replayer {
+	return &Replayer{Dir: dir}
+}
+
+// RoundTrip responds with the request's fixture,
+// and returns an error if there is no fixture for the request.
+// A fixture without exchange file, e.g. written by hand, is served for any query of its content and object.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := FixtureName(req.URL)
+	if recorded, ok := recordedRequest(filepath.Join(r.Dir, name)); ok && recorded != exchangeRequest(req) {
+		name = hashedFixtureName(req.URL)
+	}
+	p := filepath.Join(r.Dir, name)
+	body, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to find fixture %v of %v: %v", name, req.URL.Path, err)
+	}
+	statusCode := http.StatusOK
+	if exchange, err := ioutil.ReadFile(p + exchangeFileExt); err == nil {
+		lines := strings.Split(string(exchange), "\n")
+		if len(lines) < 2 || len(strings.Fields(lines[1])) == 0 {
+			return nil, fmt.Errorf("Unable to parse fixture's status: %v", name+exchangeFileExt)
+		}
+		statusCode, err = strconv.Atoi(strings.Fields(lines[1])[0])
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse fixture's status: %v", err)
+		}
+	}
+
+	header := http.Header{}
+	if contentType := fixtureContentType(path.Ext(name), body); contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// fixtureContentType guesses the content type of the fixture.
+// PRTG reports errors in XML even for JSON endpoints, so the body wins over the extension.
+func fixtureContentType(ext string, body []byte) string {
+	switch sniffContent(body) {
+	case "xml":
+		return "text/xml; charset=UTF-8"
+	case "json":
+		return "application/json"
+	}
+	contentTypes := map[string]string{".csv": "text/csv; charset=UTF-8", ".htm": "text/html; charset=UTF-8"}
+	if contentType, ok := contentTypes[ext]; ok {
+		return contentType
+	}
+	return mime.TypeByExtension(ext)
+}
@@ -0,0 +1,147 @@
+package prtg
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixtureName(t *testing.T) {
+	testCases := []struct {
+		url  string
+		name string
+	}{
+		{"http://prtg/api/getsensordetails.json?id=9321&username=user&password=pass", "prtg_sensor_9321.json"},
+		{"http://prtg/api/getsensordetails.json?passhash=000&id=9321&username=user", "prtg_sensor_9321.json"},
+		{"http://prtg/api/table.json?content=sensors&columns=objid,sensor&id=9301", "prtg_sensor-list_9301.json"},
+		{"http://prtg/api/table.xml?id=0&content=sensortree&username=user", "prtg_sensortree_0.xml"},
+		{"http://prtg/api/historicdata.json?id=14254&avg=0", "prtg_histdata_14254.json"},
+		{"http://prtg/api/downloadreport.htm?id=2001&format=pdf", "prtg_report_2001.pdf"},
+		{"http://prtg/api/status.json", "prtg_status.json"},
+		{"http://prtg/api/pause.htm?action=1&id=9302", "prtg_pause_9302.htm"},
+	}
+	for _, tc := range testCases {
+		u, _ := url.Parse(tc.url)
+		if name := FixtureName(u); name != tc.name {
+			t.Errorf("Fixture's name of %v is %v instead of %v", tc.url, name, tc.name)
+		}
+	}
+
+	// The hashed name depends on the whole query, except the credentials
+	u1, _ := url.Parse("http://prtg/api/historicdata.json?id=9321&avg=0")
+	u2, _ := url.Parse("http://prtg/api/historicdata.json?id=9321&avg=3600")
+	u3, _ := url.Parse("http://prtg/api/historicdata.json?id=9321&avg=3600&username=user")
+	if name := hashedFixtureName(u1); name == hashedFixtureName(u2) || !strings.HasPrefix(name, "prtg_histdata_9321_") {
+		t.Errorf("Fixture's hashed name of different queries should be different: %v", name)
+	}
+	if hashedFixtureName(u2) != hashedFixtureName(u3) {
+		t.Errorf("Fixture's hashed name should not depend on the credentials: %v", hashedFixtureName(u3))
+	}
+}
+
+func TestReplayFixtures(t *testing.T) {
+	// The fixtures written by hand are served for any query
+	client := NewClient("http://prtg.invalid", "user", "pass")
+	client.Transport = NewReplayer(filepath.Join("..", "fixtures"))
+	sensors, err := client.GetSensorList(9301, nil)
+	if err != nil || len(sensors) == 0 {
+		t.Errorf("There should be sensors instead of %v, error: %v", len(sensors), err)
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetSensorDetailsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.FormValue("id") == "9000" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, loadfixture("/prtg_version_error.json"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_sensor_9321.json"))
+	})
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_sensor-list_9301.json"))
+	})
+	httpServer := setup(mux)
+
+	dir, err := ioutil.TempDir("", "prtg-fixtures")
+	if err != nil {
+		t.Fatalf("Unable to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Record
+	client := NewClient(httpServer.URL, "user", "secret")
+	client.Transport = NewRecorder(dir, nil)
+	recordedDetail, err := client.GetSensorDetail(9321)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	recordedList, err := client.GetSensorList(9301, nil)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	// The same list with other columns doesn't overwrite it
+	if _, err := client.GetSensorList(9301, []string{"objid"}); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if _, err := client.GetSensorDetail(9000); err == nil {
+		t.Errorf("Since the sensor doesn't exist, an error should occur.")
+	}
+	httpServer.Close()
+
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 8 {
+		t.Errorf("There should be 4 fixtures with their exchanges instead of %v file(s)", len(files))
+	}
+	for _, f := range files {
+		content, _ := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if strings.Contains(f.Name(), "secret") || strings.Contains(string(content), "secret") {
+			t.Errorf("Fixture %v should not contain the password", f.Name())
+		}
+	}
+	exchange, _ := ioutil.ReadFile(filepath.Join(dir, "prtg_sensor_9000.json"+exchangeFileExt))
+	if string(exchange) != "GET /api/getsensordetails.json?id=9000\n400 Bad Request\n" {
+		t.Errorf("Unexpected exchange: %q", exchange)
+	}
+
+	// Replay with other credentials and without the server
+	client = NewClientWithHashedPass("http://prtg.invalid", "other", "000000000")
+	client.Transport = NewReplayer(dir)
+	replayedDetail, err := client.GetSensorDetail(9321)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	} else if recordedDetail != nil && replayedDetail.Name != recordedDetail.Name {
+		t.Errorf("Replayed sensor is %v instead of %v", replayedDetail.Name, recordedDetail.Name)
+	}
+	replayedList, err := client.GetSensorList(9301, nil)
+	if err != nil || len(replayedList) != len(recordedList) {
+		t.Errorf("There should be %v sensors instead of %v, error: %v", len(recordedList), len(replayedList), err)
+	}
+	if _, err := client.GetSensorList(9301, []string{"objid"}); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if _, err := client.GetSensorList(9301, []string{"objid", "host"}); err == nil {
+		t.Errorf("Since the columns aren't recorded, an error should occur.")
+	}
+	_, err = client.GetSensorDetail(9000)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("It should be bad request but error: %v", err)
+	}
+
+	// Not recorded
+	if _, err := client.GetSensorDetail(1); err == nil || !strings.Contains(err.Error(), "prtg_sensor_1.json") {
+		t.Errorf("Since the fixture doesn't exist, an error should occur: %v", err)
+	}
+}
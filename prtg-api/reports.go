@@ -0,0 +1,126 @@
+package prtg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ReportFormat is the file format of a generated report.
+type ReportFormat int
+
+// Report formats
+const (
+	ReportPDF ReportFormat = iota
+	ReportHTML
+	ReportCSV
+)
+
+var reportFormats = map[ReportFormat]struct {
+	name        string
+	contentType string
+}{
+	ReportPDF:  {"pdf", "application/pdf"},
+	ReportHTML: {"html", "text/html; charset=UTF-8"},
+	ReportCSV:  {"csv", "text/csv; charset=UTF-8"},
+}
+
+func (f ReportFormat) String() string {
+	if format, ok := reportFormats[f]; ok {
+		return format.name
+	}
+	return fmt.Sprintf("ReportFormat(%d)", int(f))
+}
+
+var defaultReportListCols = []string{"objid", "name", "template", "period", "schedule", "email", "lastrun"}
+
+// GetReportList returns the reports configured in PRTG.
+// A server without reports returns an empty list rather than an error.
+func (c *Client) GetReportList(columns []string) ([]PrtgReport, error) {
+	// if columns is nil, use the default column's entry instead
+	if columns == nil {
+		columns = defaultReportListCols
+	}
+
+	// Reports don't belong to the object tree, so the root is requested
+	content := "reports"
+	reportListResp, err := c.getTableList(0, content, columns)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get report list data: %v", err)
+	}
+	if reportListResp.Reports == nil {
+		return []PrtgReport{}, nil
+	}
+
+	// Return report list
+	return reportListResp.Reports, nil
+}
+
+// RunReport triggers the report covering the start and end of date's boundaries.
+// PRTG generates the report in background, download it afterward with DownloadReport.
+func (c *Client) RunReport(id int64, startDate, endDate time.Time) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return fmt.Errorf("Id should be more than or equals to zero")
+	}
+	for _, date := range []time.Time{startDate, endDate} {
+		if err := validateDateFormat(date); err != nil {
+			return err
+		}
+	}
+	if !startDate.Before(endDate) {
+		return fmt.Errorf("Start date should be before end date")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("sdate", startDate.Format(dateFormat))
+	q.Set("edate", endDate.Format(dateFormat))
+	if err := c.requestAction(RunReportEndpoint, q); err != nil {
+		return fmt.Errorf("Unable to run report: %v", err)
+	}
+	return nil
+}
+
+// DownloadReport writes the report's latest generated file in the format into w,
+// and returns its content type.
+func (c *Client) DownloadReport(w io.Writer, id int64, format ReportFormat) (string, error) {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return "", fmt.Errorf("Id should be more than or equals to zero")
+	}
+	reportFormat, ok := reportFormats[format]
+	if !ok {
+		return "", fmt.Errorf("Unknown report format: %v", format)
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("format", reportFormat.name)
+	body, _, err := c.getResponseBody(DownloadReportEndpoint, q)
+	if err != nil {
+		return "", fmt.Errorf("Unable to download report: %v", err)
+	}
+	if !isReportContent(format, body) {
+		return "", fmt.Errorf("Unable to download report: Response's content is not %v", format)
+	}
+	if _, err := w.Write(body); err != nil {
+		return "", fmt.Errorf("Unable to write report: %v", err)
+	}
+	return reportFormat.contentType, nil
+}
+
+// isReportContent reports whether the body is a report in the format,
+// rather than PRTG's error in XML or JSON.
+func isReportContent(format ReportFormat, body []byte) bool {
+	switch format {
+	case ReportPDF:
+		return bytes.HasPrefix(body, []byte("%PDF-"))
+	case ReportHTML:
+		return bytes.Contains(bytes.ToLower(body), []byte("<html"))
+	}
+	return len(body) > 0 && sniffContent(body) == ""
+}
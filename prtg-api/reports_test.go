@@ -0,0 +1,144 @@
+package prtg
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetReportList(t *testing.T) {
+	empty := false
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.FormValue("content") != "reports" {
+			return
+		}
+		if empty {
+			fmt.Fprint(w, loadfixture("/prtg_report-list_9000_empty.json"))
+			return
+		}
+		fmt.Fprint(w, loadfixture("/prtg_report-list_0.json"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	reports, err := client.GetReportList(nil)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if len(reports) != 3 {
+		t.Errorf("There should be 3 reports instead of %v", len(reports))
+		return
+	}
+	if reports[0].ObjectId != 2001 || reports[0].Name != "Customer A Monthly Availability" ||
+		reports[0].Period != "Previous month" || reports[0].Email != "noc@example.com" {
+		t.Errorf("Unexpected report: %+v", reports[0])
+	}
+	lastRun, ok := reports[0].LastRunTime()
+	expected := time.Date(2019, time.December, 1, 6, 0, 12, 0, time.UTC)
+	if !ok || lastRun.Sub(expected) > time.Second || expected.Sub(lastRun) > time.Second {
+		t.Errorf("Report's last run is %v instead of %v", lastRun, expected)
+	}
+	if _, ok := reports[2].LastRunTime(); ok {
+		t.Errorf("Report %v has never run", reports[2].Name)
+	}
+
+	empty = true
+	if reports, err := client.GetReportList(nil); err != nil || reports == nil || len(reports) != 0 {
+		t.Errorf("There should be an empty list instead of %v, error: %v", reports, err)
+	}
+}
+
+func TestRunReport(t *testing.T) {
+	var query map[string]string
+	mux := new(http.ServeMux)
+	mux.HandleFunc(RunReportEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		query = map[string]string{"id": r.FormValue("id"), "sdate": r.FormValue("sdate"), "edate": r.FormValue("edate")}
+		if r.FormValue("id") == "9000" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	start := time.Date(2019, time.November, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC)
+	if err := client.RunReport(2001, start, end); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if query["id"] != "2001" || query["sdate"] != "2019-11-01-00-00-00" || query["edate"] != "2019-12-01-00-00-00" {
+		t.Errorf("Unexpected query: %v", query)
+	}
+	if err := client.RunReport(9000, start, end); err == nil {
+		t.Errorf("Since the report doesn't exist, an error should occur.")
+	}
+
+	// Invalid input
+	if err := client.RunReport(-1, start, end); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+	if err := client.RunReport(2001, end, start); err == nil {
+		t.Errorf("Since the start is after the end, an error should occur.")
+	}
+	if err := client.RunReport(2001, time.Time{}, end); err == nil {
+		t.Errorf("Since the start is empty, an error should occur.")
+	}
+}
+
+func TestDownloadReport(t *testing.T) {
+	mux := new(http.ServeMux)
+	mux.HandleFunc(DownloadReportEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("id") == "9000" {
+			responsesXmlOk(w, "/prtg_version_error.json")
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_report_2001."+r.FormValue("format")))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	testCases := []struct {
+		format      ReportFormat
+		fixture     string
+		contentType string
+	}{
+		{ReportPDF, "/prtg_report_2001.pdf", "application/pdf"},
+		{ReportHTML, "/prtg_report_2001.html", "text/html; charset=UTF-8"},
+		{ReportCSV, "/prtg_report_2001.csv", "text/csv; charset=UTF-8"},
+	}
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		contentType, err := client.DownloadReport(&buf, 2001, tc.format)
+		if err != nil {
+			t.Errorf("[%v] It should be success but error: %v", tc.format, err)
+			continue
+		}
+		if contentType != tc.contentType || buf.String() != loadfixture(tc.fixture) {
+			t.Errorf("[%v] Unexpected report of %v, %v bytes", tc.format, contentType, buf.Len())
+		}
+
+		// PRTG's error is not a report
+		if _, err := client.DownloadReport(&buf, 9000, tc.format); err == nil {
+			t.Errorf("[%v] Since the response is not a report, an error should occur.", tc.format)
+		}
+	}
+
+	// Invalid input
+	if _, err := client.DownloadReport(new(bytes.Buffer), -1, ReportPDF); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+	if _, err := client.DownloadReport(new(bytes.Buffer), 2001, 9); err == nil {
+		t.Errorf("Since the format is unknown, an error should occur.")
+	}
+}
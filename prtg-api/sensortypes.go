@@ -0,0 +1,146 @@
+package prtg
+
+import (
+	"fmt"
+	"html"
+	"sync"
+)
+
+// PrtgSensorType contains a sensor type supported by a probe or device.
+type PrtgSensorType struct {
+	// ID is the sensor type passed to AddSensor, e.g. "ping"
+	ID          string
+	Name        string
+	Family      []string
+	Description string
+	Help        string
+	// ManuallyAddable is false for the sensor types created by PRTG only, e.g. the probe's health
+	ManuallyAddable bool
+	// NotAddableMessage explains why the sensor type can't be added, if any
+	NotAddableMessage string
+	// InUse if any sensor of this type exists on the server
+	InUse bool
+}
+
+type prtgSensorTypesResponse struct {
+	SensorTypes []struct {
+		ID                prtgScalar `json:"id"`
+		Name              prtgScalar `json:"name"`
+		Family            []string   `json:"family"`
+		Description       prtgScalar `json:"description"`
+		Help              prtgScalar `json:"help"`
+		ManuallyAddable   prtgScalar `json:"manuallyaddable"`
+		NotAddableMessage prtgScalar `json:"notaddablemessage"`
+	} `json:"sensortypes"`
+}
+
+type prtgSensorTypesInUseResponse struct {
+	Types []string `json:"types"`
+}
+
+// sensorTypeCatalog contains the sensor types per object id of the client's server, without whether they're in use.
+// It's dropped once the server reports another version, since an upgrade may change the sensor types.
+type sensorTypeCatalog struct {
+	mu      sync.Mutex
+	version string
+	types   map[int64][]PrtgSensorType
+}
+
+// get returns the kept sensor types of the object, and the server's version they're kept for.
+func (sc *sensorTypeCatalog) get(id int64) ([]PrtgSensorType, string, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	types, ok := sc.types[id]
+	return types, sc.version, ok
+}
+
+func (sc *sensorTypeCatalog) set(version string, id int64, types []PrtgSensorType) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if sc.version != version || sc.types == nil {
+		sc.version, sc.types = version, map[int64][]PrtgSensorType{}
+	}
+	sc.types[id] = types
+}
+
+// GetSensorTypes returns the sensor types supported by the probe or device.
+// The types are kept per object, and requested again once the server is seen with another version,
+// either by a request detecting the response format, or when the types of another object are requested.
+// Whether a type is in use is requested on every call, since it changes whenever a sensor is added or deleted.
+func (c *Client) GetSensorTypes(id int64) ([]PrtgSensorType, error) {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return nil, fmt.Errorf("Id should be more than or equals to zero")
+	}
+
+	var catalog []PrtgSensorType
+	cached := false
+	if c.sensorTypes != nil {
+		var version string
+		catalog, version, cached = c.sensorTypes.get(id)
+		if _, detected := c.DetectedFormat(); detected != "" && detected != version {
+			cached = false
+		}
+	}
+	if !cached {
+		var err error
+		if catalog, err = c.requestSensorTypes(id); err != nil {
+			return nil, err
+		}
+	}
+
+	var inUseResp prtgSensorTypesInUseResponse
+	if err := c.getPrtgResponse(GetSensorTypesInUseEndpoint, c.getTemplateUrlQuery(), &inUseResp); err != nil {
+		return nil, fmt.Errorf("Unable to get sensor types in use: %v", err)
+	}
+	inUse := map[string]bool{}
+	for _, sensorType := range inUseResp.Types {
+		inUse[sensorType] = true
+	}
+	types := make([]PrtgSensorType, len(catalog))
+	for i, sensorType := range catalog {
+		sensorType.InUse = inUse[sensorType.ID]
+		types[i] = sensorType
+	}
+	return types, nil
+}
+
+// requestSensorTypes requests the sensor types of the object, and keeps them for the server's version.
+func (c *Client) requestSensorTypes(id int64) ([]PrtgSensorType, error) {
+	status, err := c.GetStatus()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get sensor types: %v", err)
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	var typesResp prtgSensorTypesResponse
+	if err := c.getPrtgResponse(GetSensorTypesEndpoint, q, &typesResp); err != nil {
+		return nil, fmt.Errorf("Unable to get sensor types: %v", err)
+	}
+	if len(typesResp.SensorTypes) <= 0 {
+		return nil, fmt.Errorf("No Data Found")
+	}
+
+	types := make([]PrtgSensorType, 0, len(typesResp.SensorTypes))
+	for _, raw := range typesResp.SensorTypes {
+		// PRTG escapes the description and help as HTML
+		sensorType := PrtgSensorType{
+			ID:                trimWeirdCharacter(string(raw.ID)),
+			Name:              trimWeirdCharacter(string(raw.Name)),
+			Family:            raw.Family,
+			Description:       html.UnescapeString(trimWeirdCharacter(string(raw.Description))),
+			Help:              html.UnescapeString(trimWeirdCharacter(string(raw.Help))),
+			NotAddableMessage: trimWeirdCharacter(string(raw.NotAddableMessage)),
+		}
+		if sensorType.ManuallyAddable, err = parsePrtgBool(string(raw.ManuallyAddable)); err != nil {
+			return nil, fmt.Errorf("Unable to parse manuallyaddable of %v: %v", sensorType.ID, err)
+		}
+		types = append(types, sensorType)
+	}
+	if c.sensorTypes != nil {
+		c.sensorTypes.set(status.Version, id, types)
+	}
+	return types, nil
+}
@@ -0,0 +1,100 @@
+package prtg
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetSensorTypes(t *testing.T) {
+	version := "18.2.41.1636"
+	inUse := loadfixture("/prtg_sensortypesinuse.json")
+	requests := map[string]int{}
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetStatusEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, strings.Replace(loadfixture("/prtg_status.json"), "18.2.41.1636", version, 1))
+	})
+	mux.HandleFunc(GetSensorDetailsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, strings.Replace(loadfixture("/prtg_version.json"), "18.2.41.1636", version, 1))
+	})
+	mux.HandleFunc(GetSensorTypesEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.FormValue("id") != "2050" {
+			fmt.Fprint(w, `{"sensortypes":[]}`)
+			return
+		}
+		fmt.Fprint(w, loadfixture("/prtg_sensortypes_2050.json"))
+	})
+	mux.HandleFunc(GetSensorTypesInUseEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, inUse)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	types, err := client.GetSensorTypes(2050)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if len(types) != 3 {
+		t.Errorf("There should be 3 sensor types instead of %v", len(types))
+		return
+	}
+	ping := types[0]
+	if ping.ID != "ping" || ping.Name != "Ping" || !ping.ManuallyAddable || !ping.InUse || len(ping.Family) != 2 {
+		t.Errorf("Unexpected sensor type: %+v", ping)
+	}
+	if !strings.Contains(ping.Help, `("Ping")`) {
+		t.Errorf("Sensor type's help should be unescaped: %v", ping.Help)
+	}
+	if traffic := types[1]; !traffic.ManuallyAddable || traffic.InUse {
+		t.Errorf("Unexpected sensor type: %+v", traffic)
+	}
+	if health := types[2]; health.ManuallyAddable || health.NotAddableMessage == "" {
+		t.Errorf("Unexpected sensor type: %+v", health)
+	}
+
+	// Kept for the same version without asking the server's version, while the types in use are requested again
+	inUse = `{"types":["ping","snmptraffic"]}`
+	types, err = client.GetSensorTypes(2050)
+	if err != nil || !types[1].InUse {
+		t.Errorf("The traffic sensor type should be in use now: %+v, error: %v", types, err)
+	}
+	expected := map[string]int{GetStatusEndpoint: 1, GetSensorTypesEndpoint: 1, GetSensorTypesInUseEndpoint: 2}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Requests are %v instead of %v", requests, expected)
+	}
+
+	// Requested again once another version is detected
+	version = "19.1.48.2868"
+	if _, err := client.GetPrtgVersion(); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if _, err := client.GetSensorTypes(2050); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if requests[GetStatusEndpoint] != 2 || requests[GetSensorTypesEndpoint] != 2 {
+		t.Errorf("Sensor types should be requested again after an upgrade: %v", requests)
+	}
+
+	if _, err := client.GetSensorTypes(9000); err == nil {
+		t.Errorf("Since there is no sensor type, an error should occur.")
+	}
+	if _, err := client.GetSensorTypes(-1); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+}
@@ -0,0 +1,198 @@
+package prtg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// downtimeChannel is the name of the channel PRTG adds to every sensor's historic data,
+// the percentage of the interval the sensor was down.
+const downtimeChannel = "Downtime"
+
+// PrtgOutage contains a period the sensor was down, taken from PRTG's log.
+type PrtgOutage struct {
+	SensorID int64
+	Start    time.Time
+	End      time.Time
+	// Ongoing is true when the sensor was still down at the end of the period,
+	// End is then the end of the period
+	Ongoing bool
+	// Message of the log entry the outage started with
+	Message string
+}
+
+// Duration returns how long the sensor was down within the period.
+func (o *PrtgOutage) Duration() time.Duration {
+	return o.End.Sub(o.Start)
+}
+
+// PrtgSLA contains the availability of a sensor, or a set of sensors, within a period.
+//
+// Availability and Downtime are weighted by the historic data's coverage,
+// so a period without monitoring data counts as neither up nor down.
+// Outages are taken from the log of status changes.
+type PrtgSLA struct {
+	SensorIDs []int64
+	Start     time.Time
+	End       time.Time
+	// Availability in percent of the monitored time
+	Availability float64
+	// Coverage in percent of the period, and of the sensors, which has monitoring data
+	Coverage float64
+	// Monitored is the time covered by monitoring data
+	Monitored time.Duration
+	Downtime  time.Duration
+	Outages   []PrtgOutage
+	// MTTR is the mean duration of the ended outages, zero if there's none
+	MTTR time.Duration
+}
+
+// GetSLA returns the sensor's availability between the start and end of date's boundaries.
+// The period may be longer than 31 days, e.g. a quarter or a year.
+// The historic data is averaged per average seconds, which should be more than zero.
+func (c *Client) GetSLA(id, average int64, startDate, endDate time.Time) (*PrtgSLA, error) {
+	// Validate input
+	if average == 0 {
+		return nil, fmt.Errorf("Average should be more than zero")
+	}
+
+	histData, err := c.GetHistoricDataRange(id, average, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	sla := PrtgSLA{SensorIDs: []int64{id}, Start: startDate, End: endDate}
+	interval := time.Duration(average) * time.Second
+	var monitored, downtime float64
+	for _, data := range histData {
+		coverage, downPercent, ok, err := parseDowntime(data)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to calculate SLA of sensor %v: %v", id, err)
+		}
+		if !ok {
+			continue
+		}
+		covered := interval.Seconds() * coverage / 100
+		monitored += covered
+		downtime += covered * downPercent / 100
+	}
+	sla.Monitored = secondsToDuration(monitored)
+	sla.Downtime = secondsToDuration(downtime)
+
+	messages, err := c.GetMessages(id, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	sla.Outages = outagesFromMessages(id, messages, startDate, endDate)
+	sla.calculate()
+	return &sla, nil
+}
+
+// CombineSLA returns the availability of the set of sensors.
+// The sensors' monitored time and downtime are summed, so each sensor weighs by its coverage.
+// The period is the union of the SLAs' periods.
+func CombineSLA(slas ...PrtgSLA) PrtgSLA {
+	combined := PrtgSLA{SensorIDs: []int64{}, Outages: []PrtgOutage{}}
+	for i, sla := range slas {
+		if i == 0 || sla.Start.Before(combined.Start) {
+			combined.Start = sla.Start
+		}
+		if i == 0 || sla.End.After(combined.End) {
+			combined.End = sla.End
+		}
+		combined.SensorIDs = append(combined.SensorIDs, sla.SensorIDs...)
+		combined.Monitored += sla.Monitored
+		combined.Downtime += sla.Downtime
+		combined.Outages = append(combined.Outages, sla.Outages...)
+	}
+	sort.SliceStable(combined.Outages, func(i, j int) bool {
+		return combined.Outages[i].Start.Before(combined.Outages[j].Start)
+	})
+	combined.calculate()
+	return combined
+}
+
+// calculate derives the percentages and MTTR from the durations and outages.
+func (s *PrtgSLA) calculate() {
+	s.Availability, s.Coverage, s.MTTR = 0, 0, 0
+	if s.Monitored > 0 {
+		s.Availability = 100 * (1 - s.Downtime.Seconds()/s.Monitored.Seconds())
+	}
+	if period := s.End.Sub(s.Start).Seconds() * float64(len(s.SensorIDs)); period > 0 {
+		s.Coverage = math.Min(100, 100*s.Monitored.Seconds()/period)
+	}
+	var repaired time.Duration
+	var count int64
+	for _, outage := range s.Outages {
+		if !outage.Ongoing {
+			repaired += outage.Duration()
+			count++
+		}
+	}
+	if count > 0 {
+		s.MTTR = repaired / time.Duration(count)
+	}
+}
+
+// parseDowntime returns the record's coverage and downtime in percent,
+// and false if the record has no monitoring data.
+func parseDowntime(data PrtgHistoricData) (float64, float64, bool, error) {
+	coverage, err := data.Coverage()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	_, formatted := data[downtimeChannel]
+	_, raw := data[downtimeChannel+rawSuffix]
+	if !formatted && !raw {
+		return 0, 0, false, fmt.Errorf("Historic data has no %v channel", downtimeChannel)
+	}
+	downtime, ok := data.Value(downtimeChannel)
+	if !ok {
+		downtime, ok = data.RawValue(downtimeChannel)
+	}
+	if !ok || coverage <= 0 {
+		return 0, 0, false, nil
+	}
+	return math.Min(coverage, 100), math.Max(0, math.Min(downtime, 100)), true, nil
+}
+
+// outagesFromMessages pairs the sensor's down and recovery log entries into outages within the period.
+// A recovery without a preceding down means the sensor was down since the start of the period.
+func outagesFromMessages(id int64, messages []PrtgMessage, startDate, endDate time.Time) []PrtgOutage {
+	outages := []PrtgOutage{}
+	var current *PrtgOutage
+	seen := false
+	for _, message := range messages {
+		if message.ObjectId != id {
+			continue
+		}
+		switch {
+		case isDownStatus(message.Status):
+			if current == nil {
+				current = &PrtgOutage{SensorID: id, Start: message.DateTime, Message: message.Message}
+			}
+		case isRecoveryStatus(message.Status):
+			if current == nil && !seen {
+				current = &PrtgOutage{SensorID: id, Start: startDate}
+			}
+			if current != nil {
+				current.End = message.DateTime
+				outages = append(outages, *current)
+				current = nil
+			}
+		default:
+			continue
+		}
+		seen = true
+	}
+	if current != nil {
+		current.End, current.Ongoing = endDate, true
+		outages = append(outages, *current)
+	}
+	return outages
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Round(seconds * float64(time.Second)))
+}
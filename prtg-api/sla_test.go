@@ -0,0 +1,130 @@
+package prtg
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetSLA(t *testing.T) {
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetHistoricDatasEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.FormValue("id") != "2001" {
+			fmt.Fprint(w, loadfixture("/prtg_histdata_14254.json"))
+			return
+		}
+		fmt.Fprint(w, loadfixture("/prtg_histdata_downtime_2001.json"))
+	})
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_message-list_2001.json"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	startDate := time.Date(2018, time.June, 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.Add(6 * time.Hour)
+	sla, err := client.GetSLA(2001, 3600, startDate, endDate)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	// 4.5 hours are monitored, and the sensor is down for 15 minutes twice
+	if sla.Monitored != 270*time.Minute || sla.Downtime != 30*time.Minute {
+		t.Errorf("Monitored and downtime are %v and %v instead of 4h30m and 30m", sla.Monitored, sla.Downtime)
+	}
+	if math.Abs(sla.Availability-88.8889) > 0.0001 || sla.Coverage != 75 {
+		t.Errorf("Availability and coverage are %v and %v instead of 88.8889 and 75", sla.Availability, sla.Coverage)
+	}
+
+	// The outages are taken from the sensor's log only
+	if len(sla.Outages) != 3 {
+		t.Errorf("There should be 3 outages instead of %v", len(sla.Outages))
+		return
+	}
+	first := sla.Outages[0]
+	if !first.Start.Equal(startDate.Add(90*time.Minute)) || first.Duration() != 15*time.Minute || first.Ongoing ||
+		first.Message != "Request timed out (ping: 10 s)" {
+		t.Errorf("Unexpected outage: %+v", first)
+	}
+	if last := sla.Outages[2]; !last.Ongoing || !last.End.Equal(endDate) || last.Duration() != 10*time.Minute {
+		t.Errorf("Unexpected ongoing outage: %+v", last)
+	}
+	// The ongoing outage is not repaired yet
+	if sla.MTTR != 15*time.Minute {
+		t.Errorf("MTTR is %v instead of 15m", sla.MTTR)
+	}
+
+	// A period beyond 31 days is requested in chunks
+	sla, err = client.GetSLA(2001, 3600, startDate, startDate.AddDate(0, 3, 0))
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	} else if sla.Downtime != 30*time.Minute {
+		t.Errorf("Downtime of the quarter is %v instead of 30m", sla.Downtime)
+	}
+
+	// Invalid input
+	if _, err := client.GetSLA(2001, 0, startDate, endDate); err == nil {
+		t.Errorf("Since the average is zero, an error should occur.")
+	}
+	if _, err := client.GetSLA(2001, 3600, endDate, startDate); err == nil {
+		t.Errorf("Since the start date is after the end date, an error should occur.")
+	}
+	if _, err := client.GetSLA(14254, 3600, startDate, endDate); err == nil {
+		t.Errorf("Since the historic data has no downtime channel, an error should occur.")
+	}
+}
+
+func TestOutagesFromMessages(t *testing.T) {
+	startDate := time.Date(2018, time.June, 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.Add(time.Hour)
+	messages := []PrtgMessage{
+		{ObjectId: 1, DateTime: startDate.Add(10 * time.Minute), Status: "Up"},
+		{ObjectId: 1, DateTime: startDate.Add(20 * time.Minute), Status: "Down"},
+		{ObjectId: 1, DateTime: startDate.Add(25 * time.Minute), Status: "Down (Acknowledged)"},
+		{ObjectId: 1, DateTime: startDate.Add(30 * time.Minute), Status: "Paused"},
+	}
+
+	// The sensor was down since the start of the period
+	outages := outagesFromMessages(1, messages, startDate, endDate)
+	if len(outages) != 2 || !outages[0].Start.Equal(startDate) || outages[0].Duration() != 10*time.Minute ||
+		outages[1].Duration() != 10*time.Minute {
+		t.Errorf("Unexpected outages: %+v", outages)
+	}
+	if outages := outagesFromMessages(2, messages, startDate, endDate); len(outages) != 0 {
+		t.Errorf("There should be no outage of other sensor instead of %v", len(outages))
+	}
+}
+
+func TestCombineSLA(t *testing.T) {
+	startDate := time.Date(2018, time.June, 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.Add(10 * time.Hour)
+	slas := []PrtgSLA{
+		{SensorIDs: []int64{1}, Start: startDate, End: endDate, Monitored: 10 * time.Hour, Downtime: time.Hour,
+			Outages: []PrtgOutage{{SensorID: 1, Start: startDate.Add(2 * time.Hour), End: startDate.Add(3 * time.Hour)}}},
+		{SensorIDs: []int64{2}, Start: startDate, End: endDate, Monitored: 5 * time.Hour,
+			Outages: []PrtgOutage{{SensorID: 2, Start: startDate.Add(time.Hour), End: endDate, Ongoing: true}}},
+	}
+	combined := CombineSLA(slas...)
+	if len(combined.SensorIDs) != 2 || combined.Monitored != 15*time.Hour || combined.Downtime != time.Hour {
+		t.Errorf("Unexpected combined SLA: %+v", combined)
+	}
+	if math.Abs(combined.Availability-93.3333) > 0.0001 || combined.Coverage != 75 || combined.MTTR != time.Hour {
+		t.Errorf("Availability, coverage, and MTTR are %v, %v, and %v instead of 93.3333, 75, and 1h",
+			combined.Availability, combined.Coverage, combined.MTTR)
+	}
+	if len(combined.Outages) != 2 || combined.Outages[0].SensorID != 2 {
+		t.Errorf("Outages should be sorted by start: %+v", combined.Outages)
+	}
+
+	empty := CombineSLA()
+	if empty.Availability != 0 || empty.Coverage != 0 {
+		t.Errorf("Unexpected empty SLA: %+v", empty)
+	}
+}
@@ -0,0 +1,228 @@
+package prtg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// prtgScalar keeps PRTG's JSON value as string, since the same property
+// may be a string, a number, a boolean, or an empty string.
+type prtgScalar string
+
+func (s *prtgScalar) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*s = prtgScalar(str)
+		return nil
+	}
+	if string(data) == "null" {
+		*s = ""
+		return nil
+	}
+	*s = prtgScalar(data)
+	return nil
+}
+
+type prtgStatusResponse struct {
+	PrtgVersion          prtgScalar `json:"PRTGVersion"`
+	Version              prtgScalar `json:"Version"`
+	Clock                prtgScalar `json:"Clock"`
+	JSClock              prtgScalar `json:"jsClock"`
+	Alarms               prtgScalar `json:"Alarms"`
+	AckAlarms            prtgScalar `json:"AckAlarms"`
+	PartialAlarms        prtgScalar `json:"PartialAlarms"`
+	NewAlarms            prtgScalar `json:"NewAlarms"`
+	UpSens               prtgScalar `json:"UpSens"`
+	WarnSens             prtgScalar `json:"WarnSens"`
+	PausedSens           prtgScalar `json:"PausedSens"`
+	UnusualSens          prtgScalar `json:"UnusualSens"`
+	UnknownSens          prtgScalar `json:"UnknownSens"`
+	NewMessages          prtgScalar `json:"NewMessages"`
+	NewTickets           prtgScalar `json:"NewTickets"`
+	BackgroundTasks      prtgScalar `json:"BackgroundTasks"`
+	CorrelationTasks     prtgScalar `json:"CorrelationTasks"`
+	AutoDiscoTasks       prtgScalar `json:"AutoDiscoTasks"`
+	ReportTasks          prtgScalar `json:"ReportTasks"`
+	EditionType          prtgScalar `json:"EditionType"`
+	MaxSensorCount       prtgScalar `json:"MaxSensorCount"`
+	TrialExpiryDays      prtgScalar `json:"TrialExpiryDays"`
+	CommercialExpiryDays prtgScalar `json:"CommercialExpiryDays"`
+	MaintExpiryDays      prtgScalar `json:"MaintExpiryDays"`
+	IsCluster            prtgScalar `json:"IsCluster"`
+	ClusterNodeName      prtgScalar `json:"ClusterNodeName"`
+	ClusterType          prtgScalar `json:"ClusterType"`
+	ReadOnlyUser         prtgScalar `json:"ReadOnlyUser"`
+	IsAdminUser          prtgScalar `json:"IsAdminUser"`
+	LowMem               prtgScalar `json:"LowMem"`
+	OverloadProtection   prtgScalar `json:"Overloadprotection"`
+	UpdateAvailable      prtgScalar `json:"PRTGUpdateAvailable"`
+}
+
+// NoExpiry is PRTG's value of the expiry days which don't apply, e.g. the trial of a commercial license.
+const NoExpiry int64 = -999999
+
+// PrtgStatus contains the PRTG's system status, as seen by the requesting user.
+type PrtgStatus struct {
+	Version string
+	// Clock of the server, in UTC if the server doesn't report its epoch time
+	Clock time.Time
+
+	// Alarms is the number of sensors in down state
+	Alarms         int64
+	AckAlarms      int64
+	PartialAlarms  int64
+	NewAlarms      int64
+	UpSensors      int64
+	WarnSensors    int64
+	PausedSensors  int64
+	UnusualSensors int64
+	UnknownSensors int64
+
+	NewMessages int64
+	NewTickets  int64
+
+	BackgroundTasks    int64
+	CorrelationTasks   int64
+	AutoDiscoveryTasks int64
+	ReportTasks        int64
+
+	// License, the expiry days are NoExpiry when they don't apply
+	EditionType           string
+	MaxSensorCount        int64
+	TrialExpiryDays       int64
+	CommercialExpiryDays  int64
+	MaintenanceExpiryDays int64
+
+	IsCluster       bool
+	ClusterNodeName string
+	ClusterType     string
+
+	ReadOnlyUser bool
+	IsAdminUser  bool
+
+	LowMemory          bool
+	OverloadProtection bool
+	UpdateAvailable    bool
+}
+
+// parsePrtgCount parses number like "1,154"
+func parsePrtgCount(str string) (int64, error) {
+	return parsePrtgInt(strings.Replace(str, ",", "", -1))
+}
+
+// typed parses the status response into PrtgStatus.
+func (r *prtgStatusResponse) typed() (*PrtgStatus, error) {
+	status := PrtgStatus{
+		Version:         trimWeirdCharacter(string(r.PrtgVersion)),
+		EditionType:     trimWeirdCharacter(string(r.EditionType)),
+		ClusterNodeName: trimWeirdCharacter(string(r.ClusterNodeName)),
+		ClusterType:     trimWeirdCharacter(string(r.ClusterType)),
+	}
+	if status.Version == "" {
+		status.Version = trimWeirdCharacter(string(r.Version))
+	}
+
+	counts := []struct {
+		name  string
+		value prtgScalar
+		dest  *int64
+	}{
+		{"Alarms", r.Alarms, &status.Alarms},
+		{"AckAlarms", r.AckAlarms, &status.AckAlarms},
+		{"PartialAlarms", r.PartialAlarms, &status.PartialAlarms},
+		{"NewAlarms", r.NewAlarms, &status.NewAlarms},
+		{"UpSens", r.UpSens, &status.UpSensors},
+		{"WarnSens", r.WarnSens, &status.WarnSensors},
+		{"PausedSens", r.PausedSens, &status.PausedSensors},
+		{"UnusualSens", r.UnusualSens, &status.UnusualSensors},
+		{"UnknownSens", r.UnknownSens, &status.UnknownSensors},
+		{"NewMessages", r.NewMessages, &status.NewMessages},
+		{"NewTickets", r.NewTickets, &status.NewTickets},
+		{"BackgroundTasks", r.BackgroundTasks, &status.BackgroundTasks},
+		{"CorrelationTasks", r.CorrelationTasks, &status.CorrelationTasks},
+		{"AutoDiscoTasks", r.AutoDiscoTasks, &status.AutoDiscoveryTasks},
+		{"ReportTasks", r.ReportTasks, &status.ReportTasks},
+		{"MaxSensorCount", r.MaxSensorCount, &status.MaxSensorCount},
+		{"TrialExpiryDays", r.TrialExpiryDays, &status.TrialExpiryDays},
+		{"CommercialExpiryDays", r.CommercialExpiryDays, &status.CommercialExpiryDays},
+		{"MaintExpiryDays", r.MaintExpiryDays, &status.MaintenanceExpiryDays},
+	}
+	var err error
+	for _, count := range counts {
+		if *count.dest, err = parsePrtgCount(string(count.value)); err != nil {
+			return nil, fmt.Errorf("Unable to parse %v: %v", count.name, err)
+		}
+	}
+	// Missing expiry days don't apply
+	for _, expiry := range []struct {
+		value prtgScalar
+		dest  *int64
+	}{
+		{r.TrialExpiryDays, &status.TrialExpiryDays},
+		{r.CommercialExpiryDays, &status.CommercialExpiryDays},
+		{r.MaintExpiryDays, &status.MaintenanceExpiryDays},
+	} {
+		if isPrtgEmpty(trimWeirdCharacter(string(expiry.value))) {
+			*expiry.dest = NoExpiry
+		}
+	}
+
+	flags := []struct {
+		name  string
+		value prtgScalar
+		dest  *bool
+	}{
+		{"IsCluster", r.IsCluster, &status.IsCluster},
+		{"ReadOnlyUser", r.ReadOnlyUser, &status.ReadOnlyUser},
+		{"IsAdminUser", r.IsAdminUser, &status.IsAdminUser},
+		{"LowMem", r.LowMem, &status.LowMemory},
+		{"Overloadprotection", r.OverloadProtection, &status.OverloadProtection},
+		{"PRTGUpdateAvailable", r.UpdateAvailable, &status.UpdateAvailable},
+	}
+	for _, flag := range flags {
+		if *flag.dest, err = parsePrtgBool(string(flag.value)); err != nil {
+			return nil, fmt.Errorf("Unable to parse %v: %v", flag.name, err)
+		}
+	}
+
+	// Prefer the epoch time, since the clock's text is in the server's time zone
+	jsClock, err := parsePrtgInt(string(r.JSClock))
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse jsClock: %v", err)
+	}
+	if jsClock > 0 {
+		status.Clock = time.Unix(0, jsClock*int64(time.Millisecond)).UTC()
+	} else if clock := trimWeirdCharacter(string(r.Clock)); clock != "" {
+		if status.Clock, err = time.Parse(prtgHistoricDateFormat, clock); err != nil {
+			return nil, fmt.Errorf("Unable to parse Clock: %v", err)
+		}
+	}
+	return &status, nil
+}
+
+// GetStatus returns PRTG's system status.
+// It's a cheap request, suitable for health checks.
+func (c *Client) GetStatus() (*PrtgStatus, error) {
+	q := c.getTemplateUrlQuery()
+	p := GetStatusEndpoint
+	var statusResp prtgStatusResponse
+	if err := c.getPrtgResponse(p, q, &statusResp); err != nil {
+		return nil, fmt.Errorf("Unable to get status: %v", err)
+	}
+	status, err := statusResp.typed()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get status: %v", err)
+	}
+	// JSON's keys are case insensitive, so other responses may have the version
+	if status.Version == "" || status.Clock.IsZero() {
+		return nil, fmt.Errorf("Unable to get status: Response is not PRTG's status")
+	}
+	return status, nil
+}
@@ -0,0 +1,60 @@
+package prtg
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetStatus(t *testing.T) {
+	fixture := "/prtg_status.json"
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetStatusEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture(fixture))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	status, err := client.GetStatus()
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	expectedClock := time.Date(2019, time.December, 7, 10, 25, 57, 0, time.UTC)
+	if status.Version != "18.2.41.1636" || !status.Clock.Equal(expectedClock) {
+		t.Errorf("Unexpected version %v and clock %v", status.Version, status.Clock)
+	}
+	if status.Alarms != 3 || status.AckAlarms != 0 || status.NewAlarms != 1 || status.UpSensors != 1154 ||
+		status.PausedSensors != 25 || status.NewMessages != 12 || status.NewTickets != 1 || status.BackgroundTasks != 2 {
+		t.Errorf("Unexpected counts: %+v", status)
+	}
+	if status.EditionType != "C" || status.MaxSensorCount != 1000 || status.TrialExpiryDays != NoExpiry ||
+		status.CommercialExpiryDays != NoExpiry || status.MaintenanceExpiryDays != 245 {
+		t.Errorf("Unexpected license: %+v", status)
+	}
+	if !status.IsCluster || status.ClusterNodeName != "PRTG Core Server (Primary)" || status.ReadOnlyUser || !status.IsAdminUser {
+		t.Errorf("Unexpected cluster and user: %+v", status)
+	}
+
+	// Malformed JSON, without epoch time
+	fixture = "/prtg_status_readonly.json"
+	status, err = client.GetStatus()
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if !status.Clock.Equal(expectedClock) || !status.ReadOnlyUser || status.IsAdminUser || status.IsCluster ||
+		status.TrialExpiryDays != 27 || status.MaintenanceExpiryDays != NoExpiry || status.UpSensors != 42 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	// Not a status
+	fixture = "/prtg_version.json"
+	if _, err := client.GetStatus(); err == nil {
+		t.Errorf("Since the response is not a status, an error should occur.")
+	}
+}
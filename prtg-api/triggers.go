@@ -0,0 +1,514 @@
+package prtg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TriggerType is the type of a notification trigger.
+type TriggerType string
+
+// Trigger types, with PRTG's class
+const (
+	// TriggerState fires when the sensor enters a state
+	TriggerState TriggerType = "state"
+	// TriggerThreshold fires when a channel's value crosses the threshold
+	TriggerThreshold TriggerType = "threshold"
+	// TriggerSpeed fires when a channel's speed crosses the threshold
+	TriggerSpeed TriggerType = "speed"
+	// TriggerVolume fires when a channel's volume within the period reaches the threshold
+	TriggerVolume TriggerType = "volume"
+	// TriggerChange fires when the sensor reports a change, e.g. a changed file
+	TriggerChange TriggerType = "change"
+)
+
+// TriggerSensorState is the sensor's state watched by a state trigger.
+type TriggerSensorState int
+
+// Sensor states, with PRTG's value
+const (
+	StateDown        TriggerSensorState = 0
+	StateWarning     TriggerSensorState = 1
+	StateUnusual     TriggerSensorState = 2
+	StatePartialDown TriggerSensorState = 3
+)
+
+var triggerSensorStates = map[string]TriggerSensorState{
+	"down":           StateDown,
+	"warning":        StateWarning,
+	"unusual":        StateUnusual,
+	"down (partial)": StatePartialDown,
+	"partial down":   StatePartialDown,
+	"partialdown":    StatePartialDown,
+}
+
+func (st TriggerSensorState) String() string {
+	switch st {
+	case StateDown:
+		return "Down"
+	case StateWarning:
+		return "Warning"
+	case StateUnusual:
+		return "Unusual"
+	case StatePartialDown:
+		return "Down (Partial)"
+	}
+	return fmt.Sprintf("TriggerSensorState(%d)", int(st))
+}
+
+// TriggerCondition compares the channel's value with the trigger's threshold.
+type TriggerCondition int
+
+// Trigger conditions, with PRTG's value
+const (
+	ConditionAbove    TriggerCondition = 0
+	ConditionBelow    TriggerCondition = 1
+	ConditionEqual    TriggerCondition = 2
+	ConditionNotEqual TriggerCondition = 3
+)
+
+var triggerConditions = map[string]TriggerCondition{
+	"above":        ConditionAbove,
+	"below":        ConditionBelow,
+	"equal to":     ConditionEqual,
+	"equals":       ConditionEqual,
+	"not equal to": ConditionNotEqual,
+	"not equals":   ConditionNotEqual,
+}
+
+func (cond TriggerCondition) String() string {
+	switch cond {
+	case ConditionAbove:
+		return "Above"
+	case ConditionBelow:
+		return "Below"
+	case ConditionEqual:
+		return "Equal To"
+	case ConditionNotEqual:
+		return "Not Equal To"
+	}
+	return fmt.Sprintf("TriggerCondition(%d)", int(cond))
+}
+
+// NoNotificationID is the id of NotificationAction when the trigger doesn't notify.
+const NoNotificationID int64 = -1
+
+// NotificationAction is the notification sent by a trigger.
+type NotificationAction struct {
+	// ID of the notification, or NoNotificationID
+	ID   int64
+	Name string
+}
+
+// NoNotification returns the action which doesn't notify.
+func NoNotification() NotificationAction {
+	return NotificationAction{ID: NoNotificationID, Name: "None"}
+}
+
+// parseNotificationAction parses PRTG's value like "300|Email to Admin", where empty means none
+func parseNotificationAction(str string) (NotificationAction, error) {
+	str = trimWeirdCharacter(str)
+	if isPrtgEmpty(str) {
+		return NoNotification(), nil
+	}
+	parts := strings.SplitN(str, "|", 2)
+	id, err := strconv.ParseInt(trimWeirdCharacter(parts[0]), 10, 64)
+	if err != nil {
+		return NotificationAction{}, fmt.Errorf("Invalid notification %q", str)
+	}
+	action := NotificationAction{ID: id}
+	if len(parts) == 2 {
+		action.Name = trimWeirdCharacter(parts[1])
+	}
+	return action, nil
+}
+
+// String returns PRTG's value of the action.
+func (a NotificationAction) String() string {
+	if a.ID < 0 {
+		return fmt.Sprintf("%v|None", NoNotificationID)
+	}
+	return fmt.Sprintf("%v|%v", a.ID, a.Name)
+}
+
+// PrtgTrigger is a notification trigger of an object.
+// Only the fields of the trigger's type apply.
+type PrtgTrigger struct {
+	// SubID identifies the trigger within the object it's defined on
+	SubID int64
+	// ParentID is the object which the trigger is defined on
+	ParentID int64
+	// Inherited if the trigger is defined on an ancestor of the requested object
+	Inherited bool
+	Type      TriggerType
+	TypeName  string
+
+	// State of a state trigger
+	State TriggerSensorState
+
+	// Channel of threshold, speed, and volume triggers, e.g. "Primary" or the channel's id
+	Channel string
+	// Condition of threshold and speed triggers
+	Condition TriggerCondition
+	// Threshold of threshold, speed, and volume triggers
+	Threshold float64
+	// UnitSize of speed and volume triggers, e.g. "KByte"
+	UnitSize string
+	// UnitTime of a speed trigger, e.g. "Second"
+	UnitTime string
+	// Period of a volume trigger, e.g. "Hour"
+	Period string
+
+	// Latency before OnNotification, of state, threshold, and speed triggers
+	Latency        time.Duration
+	OnNotification NotificationAction
+	// OffNotification is sent when the condition clears, of state, threshold, and speed triggers
+	OffNotification NotificationAction
+
+	// Escalation of a state trigger, repeated every RepeatInterval
+	EscalationLatency      time.Duration
+	EscalationNotification NotificationAction
+	RepeatInterval         time.Duration
+}
+
+// prtgTriggerRow is a row of the triggers table. PRTG returns the trigger's properties as
+// a JSON object within the content column, either as object or as string.
+type prtgTriggerRow struct {
+	ObjectId int64           `json:"objid"`
+	Content  json.RawMessage `json:"content"`
+}
+
+type prtgTriggerContent map[string]prtgScalar
+
+func (row *prtgTriggerRow) content() (prtgTriggerContent, error) {
+	raw := bytes.TrimSpace(row.Content)
+	if len(raw) > 0 && raw[0] == '"' {
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return nil, err
+		}
+		raw = []byte(str)
+	}
+	content := prtgTriggerContent{}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, fmt.Errorf("Unable to parse trigger's content: %v", err)
+	}
+	return content, nil
+}
+
+func (content prtgTriggerContent) get(key string) string {
+	return trimWeirdCharacter(string(content[key]))
+}
+
+// parseTriggerSensorState parses PRTG's raw value or its text, e.g. "0" or "Down"
+func parseTriggerSensorState(str string) (TriggerSensorState, error) {
+	if value, err := strconv.Atoi(str); err == nil {
+		return TriggerSensorState(value), nil
+	}
+	if state, ok := triggerSensorStates[strings.ToLower(str)]; ok {
+		return state, nil
+	}
+	return 0, fmt.Errorf("Unknown sensor state %q", str)
+}
+
+// parseTriggerCondition parses PRTG's raw value or its text, e.g. "0" or "Above"
+func parseTriggerCondition(str string) (TriggerCondition, error) {
+	if value, err := strconv.Atoi(str); err == nil {
+		return TriggerCondition(value), nil
+	}
+	if condition, ok := triggerConditions[strings.ToLower(str)]; ok {
+		return condition, nil
+	}
+	return 0, fmt.Errorf("Unknown condition %q", str)
+}
+
+// typed parses the trigger's content requested on the object id.
+func (content prtgTriggerContent) typed(id int64) (*PrtgTrigger, error) {
+	trigger := PrtgTrigger{
+		Type:     TriggerType(content.get("type")),
+		TypeName: content.get("typename"),
+		Channel:  content.get("channel"),
+		UnitSize: content.get("unitsize"),
+		UnitTime: content.get("unittime"),
+		Period:   content.get("period"),
+	}
+	var err error
+	if trigger.SubID, err = parsePrtgInt(content.get("subid")); err != nil {
+		return nil, fmt.Errorf("Unable to parse subid: %v", err)
+	}
+	// PRTG reports the object which the trigger is defined on as parentid
+	if trigger.ParentID, err = parsePrtgInt(content.get("parentid")); err != nil {
+		return nil, fmt.Errorf("Unable to parse parentid: %v", err)
+	}
+	if content.get("parentid") == "" {
+		trigger.ParentID = id
+	}
+	trigger.Inherited = trigger.ParentID != id
+
+	if state := content.get("nodest"); state != "" {
+		if trigger.State, err = parseTriggerSensorState(state); err != nil {
+			return nil, fmt.Errorf("Unable to parse nodest: %v", err)
+		}
+	}
+	if condition := content.get("condition"); condition != "" {
+		if trigger.Condition, err = parseTriggerCondition(condition); err != nil {
+			return nil, fmt.Errorf("Unable to parse condition: %v", err)
+		}
+	}
+	if threshold := content.get("threshold"); !isPrtgEmpty(threshold) {
+		if trigger.Threshold, err = strconv.ParseFloat(threshold, 64); err != nil {
+			return nil, fmt.Errorf("Unable to parse threshold: %v", err)
+		}
+	}
+
+	durations := []struct {
+		key  string
+		unit time.Duration
+		dest *time.Duration
+	}{
+		{"latency", time.Second, &trigger.Latency},
+		{"esclatency", time.Second, &trigger.EscalationLatency},
+		{"repeatival", time.Minute, &trigger.RepeatInterval},
+	}
+	for _, duration := range durations {
+		value, err := parsePrtgInt(content.get(duration.key))
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse %v: %v", duration.key, err)
+		}
+		*duration.dest = time.Duration(value) * duration.unit
+	}
+
+	notifications := []struct {
+		key  string
+		dest *NotificationAction
+	}{
+		{"onnotificationid", &trigger.OnNotification},
+		{"offnotificationid", &trigger.OffNotification},
+		{"escnotificationid", &trigger.EscalationNotification},
+	}
+	for _, notification := range notifications {
+		if *notification.dest, err = parseNotificationAction(content.get(notification.key)); err != nil {
+			return nil, fmt.Errorf("Unable to parse %v: %v", notification.key, err)
+		}
+	}
+	return &trigger, nil
+}
+
+// settings returns the trigger's properties of its type, as PRTG's settings.
+func (t *PrtgTrigger) settings() (map[string]string, error) {
+	seconds := func(d time.Duration) string { return fmt.Sprintf("%v", int64(d/time.Second)) }
+	settings := map[string]string{"onnotificationid": t.OnNotification.String()}
+	switch t.Type {
+	case TriggerState:
+		if t.State < StateDown || t.State > StatePartialDown {
+			return nil, fmt.Errorf("Unknown sensor state: %v", t.State)
+		}
+		settings["nodest"] = fmt.Sprintf("%v", int(t.State))
+		settings["latency"] = seconds(t.Latency)
+		settings["offnotificationid"] = t.OffNotification.String()
+		settings["esclatency"] = seconds(t.EscalationLatency)
+		settings["escnotificationid"] = t.EscalationNotification.String()
+		settings["repeatival"] = fmt.Sprintf("%v", int64(t.RepeatInterval/time.Minute))
+	case TriggerThreshold, TriggerSpeed, TriggerVolume:
+		if t.Channel == "" {
+			return nil, fmt.Errorf("Channel should not be empty for %v trigger", t.Type)
+		}
+		settings["channel"] = t.Channel
+		settings["threshold"] = strconv.FormatFloat(t.Threshold, 'f', -1, 64)
+		if t.Type == TriggerVolume {
+			if t.UnitSize == "" || t.Period == "" {
+				return nil, fmt.Errorf("Unit size and period should not be empty for volume trigger")
+			}
+			settings["unitsize"] = t.UnitSize
+			settings["period"] = t.Period
+			break
+		}
+		if t.Condition < ConditionAbove || t.Condition > ConditionNotEqual {
+			return nil, fmt.Errorf("Unknown condition: %v", t.Condition)
+		}
+		settings["condition"] = fmt.Sprintf("%v", int(t.Condition))
+		settings["latency"] = seconds(t.Latency)
+		settings["offnotificationid"] = t.OffNotification.String()
+		if t.Type == TriggerSpeed {
+			if t.UnitSize == "" || t.UnitTime == "" {
+				return nil, fmt.Errorf("Unit size and unit time should not be empty for speed trigger")
+			}
+			settings["unitsize"] = t.UnitSize
+			settings["unittime"] = t.UnitTime
+		}
+	case TriggerChange:
+	default:
+		return nil, fmt.Errorf("Unknown trigger type: %v", t.Type)
+	}
+	return settings, nil
+}
+
+// GetTriggers returns the notification triggers of the object, including the inherited ones.
+// An object without triggers returns an empty list rather than an error.
+func (c *Client) GetTriggers(id int64) ([]PrtgTrigger, error) {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return nil, fmt.Errorf("Id should be more than or equals to zero")
+	}
+
+	triggerListResp, err := c.getTableList(id, "triggers", []string{"content", "objid"})
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get trigger list data: %v", err)
+	}
+	triggers := make([]PrtgTrigger, 0, len(triggerListResp.Triggers))
+	for _, row := range triggerListResp.Triggers {
+		content, err := row.content()
+		if err != nil {
+			return nil, err
+		}
+		trigger, err := content.typed(id)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse trigger: %v", err)
+		}
+		triggers = append(triggers, *trigger)
+	}
+	return triggers, nil
+}
+
+type prtgTriggerTypesResponse struct {
+	Supported []TriggerType `json:"supported"`
+}
+
+// GetTriggerTypes returns the trigger types which can be added to the object.
+func (c *Client) GetTriggerTypes(id int64) ([]TriggerType, error) {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return nil, fmt.Errorf("Id should be more than or equals to zero")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	var typesResp prtgTriggerTypesResponse
+	if err := c.getPrtgResponse(GetTriggerTypesEndpoint, q, &typesResp); err != nil {
+		return nil, fmt.Errorf("Unable to get trigger types: %v", err)
+	}
+	return typesResp.Supported, nil
+}
+
+// ownTriggerSubIDs returns the subids of the triggers defined on the object.
+func (c *Client) ownTriggerSubIDs(id int64) (map[int64]bool, error) {
+	triggers, err := c.GetTriggers(id)
+	if err != nil {
+		return nil, err
+	}
+	subIDs := map[int64]bool{}
+	for _, trigger := range triggers {
+		if !trigger.Inherited {
+			subIDs[trigger.SubID] = true
+		}
+	}
+	return subIDs, nil
+}
+
+// editTrigger saves the trigger's settings as the subid, "new" to add a trigger.
+func (c *Client) editTrigger(id int64, subID string, trigger *PrtgTrigger) error {
+	settings, err := trigger.settings()
+	if err != nil {
+		return err
+	}
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("subid", subID)
+	q.Set("objecttype", "nodetrigger")
+	if subID == "new" {
+		q.Set("class", string(trigger.Type))
+	}
+	for key, value := range settings {
+		q.Set(key+"_"+subID, value)
+	}
+	return c.requestAction(EditSettingsEndpoint, q)
+}
+
+// AddTrigger adds the notification trigger to the object, and returns its subid.
+func (c *Client) AddTrigger(id int64, trigger *PrtgTrigger) (int64, error) {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 {
+		return 0, fmt.Errorf("Id should be more than or equals to zero")
+	}
+	if trigger == nil {
+		return 0, fmt.Errorf("Trigger should not be nil")
+	}
+	supported, err := c.GetTriggerTypes(id)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to add trigger: %v", err)
+	}
+	isSupported := false
+	for _, triggerType := range supported {
+		isSupported = isSupported || triggerType == trigger.Type
+	}
+	if !isSupported {
+		return 0, fmt.Errorf("Unable to add trigger: %v trigger is not supported by object %v", trigger.Type, id)
+	}
+
+	// The new trigger is found by comparing the object's triggers
+	before, err := c.ownTriggerSubIDs(id)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to add trigger: %v", err)
+	}
+	if err := c.editTrigger(id, "new", trigger); err != nil {
+		return 0, fmt.Errorf("Unable to add trigger: %v", err)
+	}
+	after, err := c.ownTriggerSubIDs(id)
+	if err != nil {
+		return 0, fmt.Errorf("Unable to add trigger: %v", err)
+	}
+	subIDs := []int64{}
+	for subID := range after {
+		if !before[subID] {
+			subIDs = append(subIDs, subID)
+		}
+	}
+	if len(subIDs) == 0 {
+		return 0, fmt.Errorf("Unable to add trigger: No trigger is created")
+	}
+	sort.Slice(subIDs, func(i, j int) bool { return subIDs[i] < subIDs[j] })
+	return subIDs[len(subIDs)-1], nil
+}
+
+// EditTrigger replaces the settings of the object's trigger.
+// The trigger's type can't be changed, and inherited triggers are edited on their ParentID.
+func (c *Client) EditTrigger(id, subID int64, trigger *PrtgTrigger) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 || subID < 0 {
+		return fmt.Errorf("Id and subid should be more than or equals to zero")
+	}
+	if trigger == nil {
+		return fmt.Errorf("Trigger should not be nil")
+	}
+	if err := c.editTrigger(id, fmt.Sprintf("%v", subID), trigger); err != nil {
+		return fmt.Errorf("Unable to edit trigger: %v", err)
+	}
+	return nil
+}
+
+// RemoveTrigger removes the object's trigger.
+// Inherited triggers are removed from their ParentID.
+func (c *Client) RemoveTrigger(id, subID int64) error {
+	// Validate input
+	// Make sure that id is not less than 0
+	if id < 0 || subID < 0 {
+		return fmt.Errorf("Id and subid should be more than or equals to zero")
+	}
+
+	q := c.getTemplateUrlQuery()
+	q.Set("id", fmt.Sprintf("%v", id))
+	q.Set("subid", fmt.Sprintf("%v", subID))
+	if err := c.requestAction(RemoveTriggerEndpoint, q); err != nil {
+		return fmt.Errorf("Unable to remove trigger: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package prtg
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestGetTriggers(t *testing.T) {
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if r.FormValue("content") != "triggers" {
+			return
+		}
+		if r.FormValue("id") != "2050" {
+			fmt.Fprint(w, `{"prtg-version":"18.2.41.1652","treesize":0,"triggers":[]}`)
+			return
+		}
+		fmt.Fprint(w, loadfixture("/prtg_trigger-list_2050.json"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	triggers, err := client.GetTriggers(2050)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if len(triggers) != 3 {
+		t.Errorf("There should be 3 triggers instead of %v", len(triggers))
+		return
+	}
+
+	// The content is a string
+	state := triggers[0]
+	if state.Type != TriggerState || state.SubID != 1 || state.ParentID != 0 || !state.Inherited ||
+		state.State != StateDown || state.Latency != time.Minute {
+		t.Errorf("Unexpected state trigger: %+v", state)
+	}
+	if state.OnNotification != (NotificationAction{ID: 300, Name: "Email to Admin"}) ||
+		state.EscalationNotification.ID != 301 || state.OffNotification.ID != NoNotificationID ||
+		state.EscalationLatency != 5*time.Minute || state.RepeatInterval != 10*time.Minute {
+		t.Errorf("Unexpected state trigger's notifications: %+v", state)
+	}
+
+	// The content is an object
+	threshold := triggers[1]
+	if threshold.Type != TriggerThreshold || threshold.Inherited || threshold.Channel != "Primary" ||
+		threshold.Condition != ConditionAbove || threshold.Threshold != 95.5 || threshold.Latency != 2*time.Minute {
+		t.Errorf("Unexpected threshold trigger: %+v", threshold)
+	}
+	if threshold.OffNotification != NoNotification() {
+		t.Errorf("Empty notification should be none instead of %v", threshold.OffNotification)
+	}
+	speed := triggers[2]
+	if speed.Type != TriggerSpeed || speed.Condition != ConditionBelow || speed.UnitSize != "MByte" ||
+		speed.UnitTime != "Second" || speed.Threshold != 10 {
+		t.Errorf("Unexpected speed trigger: %+v", speed)
+	}
+
+	// No trigger isn't an error
+	triggers, err = client.GetTriggers(9000)
+	if err != nil || triggers == nil || len(triggers) != 0 {
+		t.Errorf("There should be no trigger instead of %v, error: %v", len(triggers), err)
+	}
+	if _, err := client.GetTriggers(-1); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+}
+
+func TestAddEditRemoveTrigger(t *testing.T) {
+	subIDs := []string{}
+	var edited, removed url.Values
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetTriggerTypesEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"supported":["state","threshold","change"]}`)
+	})
+	mux.HandleFunc(GetTableListsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `{"prtg-version":"18.2.41.1652","treesize":0,"triggers":[`)
+		for i, subID := range subIDs {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"objid":2050,"content":{"type":"state","subid":"%v","parentid":"2050"}}`, subID)
+		}
+		fmt.Fprint(w, `]}`)
+	})
+	mux.HandleFunc(EditSettingsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		edited = r.URL.Query()
+		if r.FormValue("subid") == "new" {
+			subIDs = append(subIDs, fmt.Sprintf("%v", len(subIDs)+1))
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc(RemoveTriggerEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		removed = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	client := NewClient(httpServer.URL, "user", "pass")
+
+	trigger := &PrtgTrigger{
+		Type:                   TriggerState,
+		State:                  StateWarning,
+		Latency:                time.Minute,
+		OnNotification:         NotificationAction{ID: 300, Name: "Email to Admin"},
+		OffNotification:        NoNotification(),
+		EscalationLatency:      5 * time.Minute,
+		EscalationNotification: NotificationAction{ID: 301, Name: "SMS"},
+		RepeatInterval:         15 * time.Minute,
+	}
+	subID, err := client.AddTrigger(2050, trigger)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if subID != 1 {
+		t.Errorf("Trigger's subid is %v instead of 1", subID)
+	}
+	expected := map[string]string{
+		"id": "2050", "subid": "new", "class": "state", "objecttype": "nodetrigger", "nodest_new": "1",
+		"latency_new": "60", "onnotificationid_new": "300|Email to Admin", "offnotificationid_new": "-1|None",
+		"esclatency_new": "300", "escnotificationid_new": "301|SMS", "repeatival_new": "15",
+	}
+	for key, value := range expected {
+		if edited.Get(key) != value {
+			t.Errorf("Query %v is %v instead of %v", key, edited.Get(key), value)
+		}
+	}
+
+	threshold := &PrtgTrigger{
+		Type: TriggerThreshold, Channel: "Primary", Condition: ConditionBelow, Threshold: 0.5,
+		OnNotification: NotificationAction{ID: 302, Name: "Ticket"},
+	}
+	if err := client.EditTrigger(2050, 2, threshold); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if edited.Get("subid") != "2" || edited.Get("class") != "" || edited.Get("condition_2") != "1" ||
+		edited.Get("threshold_2") != "0.5" || edited.Get("channel_2") != "Primary" {
+		t.Errorf("Unexpected query: %v", edited)
+	}
+
+	if err := client.RemoveTrigger(2050, 2); err != nil {
+		t.Errorf("It should be success but error: %v", err)
+	}
+	if removed.Get("id") != "2050" || removed.Get("subid") != "2" {
+		t.Errorf("Unexpected query: %v", removed)
+	}
+
+	// Invalid input
+	if _, err := client.AddTrigger(2050, &PrtgTrigger{Type: TriggerSpeed, Channel: "1", UnitSize: "KByte", UnitTime: "Second"}); err == nil {
+		t.Errorf("Since the trigger type is not supported, an error should occur.")
+	}
+	if _, err := client.AddTrigger(2050, &PrtgTrigger{Type: TriggerThreshold}); err == nil {
+		t.Errorf("Since the channel is empty, an error should occur.")
+	}
+	if _, err := client.AddTrigger(2050, nil); err == nil {
+		t.Errorf("Since the trigger is nil, an error should occur.")
+	}
+	if err := client.EditTrigger(2050, 1, &PrtgTrigger{Type: TriggerState, State: 7}); err == nil {
+		t.Errorf("Since the state is unknown, an error should occur.")
+	}
+	if err := client.RemoveTrigger(-1, 1); err == nil {
+		t.Errorf("Since the id is negative, an error should occur.")
+	}
+}
@@ -0,0 +1,172 @@
+package prtg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// prtgEpoch is the origin of PRTG's datetime value, in days (OLE Automation date).
+var prtgEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// Typed parses the string properties of the sensor data into PrtgSensorDataTyped.
+func (d *PrtgSensorData) Typed() (*PrtgSensorDataTyped, error) {
+	trimmed := *d
+	trimStringFields(&trimmed)
+
+	typed := PrtgSensorDataTyped{
+		Name:             trimmed.Name,
+		SensorType:       trimmed.SensorType,
+		ProbeName:        trimmed.ProbeName,
+		ParentGroupName:  trimmed.ParentGroupName,
+		ParentDeviceName: trimmed.ParentDeviceName,
+		LastValue:        trimmed.LastValue,
+		LastMessage:      trimmed.LastMessage,
+		StatusText:       trimmed.StatusText,
+		UptimeTime:       trimmed.UptimeTime,
+		DowntimeTime:     trimmed.DowntimeTime,
+		UpDownTotal:      trimmed.UpDownTotal,
+		Info:             trimmed.Info,
+	}
+	var err error
+	if typed.Interval, err = parsePrtgDuration(trimmed.Interval); err != nil {
+		return nil, fmt.Errorf("Unable to parse interval: %v", err)
+	}
+	if typed.ParentDeviceID, err = parsePrtgInt(trimmed.ParentDeviceId); err != nil {
+		return nil, fmt.Errorf("Unable to parse parentdeviceid: %v", err)
+	}
+	if typed.Favorite, err = parsePrtgBool(trimmed.Favorite); err != nil {
+		return nil, fmt.Errorf("Unable to parse favorite: %v", err)
+	}
+	if typed.StatusID, err = parsePrtgInt(trimmed.StatusId); err != nil {
+		return nil, fmt.Errorf("Unable to parse statusid: %v", err)
+	}
+	if typed.LastUp, err = parsePrtgTime(trimmed.LastUp); err != nil {
+		return nil, fmt.Errorf("Unable to parse lastup: %v", err)
+	}
+	if typed.LastDown, err = parsePrtgTime(trimmed.LastDown); err != nil {
+		return nil, fmt.Errorf("Unable to parse lastdown: %v", err)
+	}
+	if typed.LastCheck, err = parsePrtgTime(trimmed.LastCheck); err != nil {
+		return nil, fmt.Errorf("Unable to parse lastcheck: %v", err)
+	}
+	if typed.Uptime, err = parsePrtgPercent(trimmed.Uptime); err != nil {
+		return nil, fmt.Errorf("Unable to parse uptime: %v", err)
+	}
+	if typed.Downtime, err = parsePrtgPercent(trimmed.Downtime); err != nil {
+		return nil, fmt.Errorf("Unable to parse downtime: %v", err)
+	}
+	if typed.UpDownSince, err = parsePrtgTime(trimmed.UpDownSince); err != nil {
+		return nil, fmt.Errorf("Unable to parse updownsince: %v", err)
+	}
+	return &typed, nil
+}
+
+// UnmarshalJSON decodes the sensordata object of the JSON response.
+func (d *PrtgSensorDataTyped) UnmarshalJSON(data []byte) error {
+	var raw PrtgSensorData
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	typed, err := raw.Typed()
+	if err != nil {
+		return err
+	}
+	*d = *typed
+	return nil
+}
+
+// UnmarshalXML decodes the sensordata element of the XML response.
+func (d *PrtgSensorDataTyped) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var raw PrtgSensorData
+	if err := decoder.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+	typed, err := raw.Typed()
+	if err != nil {
+		return err
+	}
+	*d = *typed
+	return nil
+}
+
+// ConvertPrtgDateTime converts PRTG's raw datetime, the days since 30 December 1899, into time.Time in UTC.
+func ConvertPrtgDateTime(days float64) time.Time {
+	// Keep the precision up to millisecond
+	ms := int64(math.Round(days * 24 * 60 * 60 * 1000))
+	return prtgEpoch.Add(time.Duration(ms) * time.Millisecond)
+}
+
+// trimStringFields trims every string field of the struct pointed by v.
+func trimStringFields(v interface{}) {
+	value := reflect.ValueOf(v).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() == reflect.String && field.CanSet() {
+			field.SetString(trimWeirdCharacter(field.String()))
+		}
+	}
+}
+
+// isPrtgEmpty reports whether PRTG's value means "no value".
+func isPrtgEmpty(str string) bool {
+	return str == "" || str == "-"
+}
+
+func parsePrtgInt(str string) (int64, error) {
+	str = trimWeirdCharacter(str)
+	if isPrtgEmpty(str) {
+		return 0, nil
+	}
+	return strconv.ParseInt(str, 10, 64)
+}
+
+func parsePrtgBool(str string) (bool, error) {
+	switch strings.ToLower(trimWeirdCharacter(str)) {
+	case "", "-", "false", "0", "no":
+		return false, nil
+	case "true", "1", "yes":
+		return true, nil
+	}
+	return false, fmt.Errorf("Invalid boolean %q", str)
+}
+
+// parsePrtgPercent parses value like "76.5359%" into 76.5359
+func parsePrtgPercent(str string) (float64, error) {
+	str = trimWeirdCharacter(strings.TrimSuffix(trimWeirdCharacter(str), "%"))
+	if isPrtgEmpty(str) {
+		return 0, nil
+	}
+	return strconv.ParseFloat(str, 64)
+}
+
+// parsePrtgDuration parses the interval in second like "60" or "60 s"
+func parsePrtgDuration(str string) (time.Duration, error) {
+	str = trimWeirdCharacter(strings.TrimSuffix(trimWeirdCharacter(str), "s"))
+	if isPrtgEmpty(str) {
+		return 0, nil
+	}
+	second, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(second * float64(time.Second)), nil
+}
+
+// parsePrtgTime parses value like "43808.3460424768 [23 s ago]"
+func parsePrtgTime(str string) (time.Time, error) {
+	fields := strings.Fields(str)
+	if len(fields) == 0 || isPrtgEmpty(fields[0]) {
+		return time.Time{}, nil
+	}
+	days, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ConvertPrtgDateTime(days), nil
+}
@@ -0,0 +1,136 @@
+package prtg
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestGetSensorDetailTyped(t *testing.T) {
+	mux := new(http.ServeMux)
+	mux.HandleFunc(GetSensorDetailsEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_sensor_9182.json"))
+	})
+	mux.HandleFunc(GetSensorDetailsEndpointXML, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, loadfixture("/prtg_sensor-detail.xml"))
+	})
+	httpServer := setup(mux)
+	defer httpServer.Close()
+	serverURL, _ := url.Parse(httpServer.URL)
+
+	client := NewClient(fmt.Sprintf("%v", serverURL), "user", "pass")
+
+	// JSON response
+	client.Format = FormatJSON
+	sensorDetail, err := client.GetSensorDetailTyped(9182)
+	if err != nil {
+		t.Errorf("Unable to get PRTG's Sensor Detail: %v", err)
+		return
+	}
+	if sensorDetail.Interval != 60*time.Second {
+		t.Errorf("Sensor's interval is %v instead of 1m0s", sensorDetail.Interval)
+	}
+	if sensorDetail.ParentDeviceID != 9179 {
+		t.Errorf("Sensor's parent device id is %v instead of 9179", sensorDetail.ParentDeviceID)
+	}
+	if sensorDetail.Favorite {
+		t.Errorf("Sensor should not be favorite")
+	}
+	if sensorDetail.StatusID != 1 {
+		t.Errorf("Sensor's status id is %v instead of 1", sensorDetail.StatusID)
+	}
+	if sensorDetail.Uptime != 100 {
+		t.Errorf("Sensor's uptime is %v instead of 100", sensorDetail.Uptime)
+	}
+	if !sensorDetail.LastDown.IsZero() {
+		t.Errorf("Sensor's last down should be zero, but %v", sensorDetail.LastDown)
+	}
+	expectedLastCheck := time.Date(2017, time.June, 23, 7, 55, 0, 0, time.UTC)
+	if !sensorDetail.LastCheck.Equal(expectedLastCheck) {
+		t.Errorf("Sensor's last check is %v instead of %v", sensorDetail.LastCheck, expectedLastCheck)
+	}
+
+	// XML response
+	client.Format = FormatXML
+	sensorDetail, err = client.GetSensorDetailTyped(7888)
+	if err != nil {
+		t.Errorf("Unable to get PRTG's Sensor Detail: %v", err)
+		return
+	}
+	if sensorDetail.Name != "SNMP System Uptime" {
+		t.Errorf("Sensor's name %v instead of SNMP System Uptime", sensorDetail.Name)
+	}
+	if sensorDetail.ParentDeviceID != 7984 {
+		t.Errorf("Sensor's parent device id is %v instead of 7984", sensorDetail.ParentDeviceID)
+	}
+	if sensorDetail.Uptime != 76.5359 || sensorDetail.Downtime != 23.4641 {
+		t.Errorf("Sensor's uptime and downtime are %v and %v instead of 76.5359 and 23.4641", sensorDetail.Uptime, sensorDetail.Downtime)
+	}
+	expectedLastCheck = time.Date(2019, time.December, 9, 8, 18, 18, 70000000, time.UTC)
+	if !sensorDetail.LastCheck.Equal(expectedLastCheck) {
+		t.Errorf("Sensor's last check is %v instead of %v", sensorDetail.LastCheck, expectedLastCheck)
+	}
+}
+
+func TestPrtgSensorDataTyped(t *testing.T) {
+	sensorData := PrtgSensorData{ParentDeviceId: " -1000 ", Interval: "60 s", StatusId: "5", LastUp: "-"}
+	typed, err := sensorData.Typed()
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if typed.ParentDeviceID != -1000 || typed.Interval != time.Minute || typed.StatusID != 5 || !typed.LastUp.IsZero() {
+		t.Errorf("Unexpected typed sensor data: %+v", typed)
+	}
+
+	// Invalid value
+	if _, err := (&PrtgSensorData{Favorite: "maybe"}).Typed(); err == nil {
+		t.Errorf("It should be error since favorite is not boolean")
+	}
+	if _, err := (&PrtgSensorData{Uptime: "abc%"}).Typed(); err == nil {
+		t.Errorf("It should be error since uptime is not a number")
+	}
+}
+
+func TestUnmarshalPrtgSensorDataTyped(t *testing.T) {
+	// XML
+	var xmlData PrtgSensorDataTyped
+	err := xml.Unmarshal([]byte(loadfixture("/prtg_sensor-detail.xml")), &xmlData)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if xmlData.StatusID != 5 || xmlData.Interval != time.Minute {
+		t.Errorf("Status id and interval are %v and %v instead of 5 and 1m0s", xmlData.StatusID, xmlData.Interval)
+	}
+
+	// JSON
+	var jsonResp struct {
+		SensorData PrtgSensorDataTyped `json:"sensordata"`
+	}
+	err = json.Unmarshal([]byte(loadfixture("/prtg_version.json")), &jsonResp)
+	if err != nil {
+		t.Errorf("It should be success but error: %v", err)
+		return
+	}
+	if jsonResp.SensorData.ParentDeviceID != -1000 || jsonResp.SensorData.Interval != 0 {
+		t.Errorf("Parent device id and interval are %v and %v instead of -1000 and 0s",
+			jsonResp.SensorData.ParentDeviceID, jsonResp.SensorData.Interval)
+	}
+
+	// Invalid value
+	if err := json.Unmarshal([]byte(`{"favorite": "maybe"}`), &jsonResp.SensorData); err == nil {
+		t.Errorf("It should be error since favorite is not boolean")
+	}
+	if err := xml.Unmarshal([]byte(`<sensordata><uptime>abc%</uptime></sensordata>`), &xmlData); err == nil {
+		t.Errorf("It should be error since uptime is not a number")
+	}
+}